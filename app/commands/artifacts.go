@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hay-kot/repomgr/app/core/config"
+	"github.com/hay-kot/repomgr/app/core/repostore"
+	"github.com/hay-kot/repomgr/app/repos"
+)
+
+func (ctrl *Controller) refresher() *repostore.Refresher {
+	return repostore.NewRefresher(
+		ctrl.store,
+		ctrl.fetchArtifact,
+		ctrl.conf.Concurrency,
+		ctrl.conf.Artifacts.Types,
+	)
+}
+
+// sourceClient returns the client for the source the repository was synced
+// from. Repositories synced before the source was stored are matched on the
+// owner and fall back to the first source.
+func (ctrl *Controller) sourceClient(repo repos.Repository) (repos.RepositoryClient, error) {
+	source, err := ctrl.repoSource(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	return ctrl.client(source.Type, source.Token())
+}
+
+// repoSource returns the configured source of the repository, see sourceClient.
+func (ctrl *Controller) repoSource(repo repos.Repository) (config.Source, error) {
+	if len(ctrl.conf.Sources) == 0 {
+		return config.Source{}, fmt.Errorf("no sources configured")
+	}
+
+	if repo.Source != "" {
+		for _, s := range ctrl.conf.Sources {
+			if s.ID() == repo.Source {
+				return s, nil
+			}
+		}
+
+		return config.Source{}, fmt.Errorf("source %s of %s is not configured", repo.Source, repo.DisplayName())
+	}
+
+	for _, s := range ctrl.conf.Sources {
+		if s.Username == repo.Owner {
+			return s, nil
+		}
+	}
+
+	return ctrl.conf.Sources[0], nil
+}
+
+// fetchArtifact implements repostore.ArtifactFetcher
+func (ctrl *Controller) fetchArtifact(ctx context.Context, repo repos.Repository, t repostore.ArtifactType) ([]byte, error) {
+	client, err := ctrl.sourceClient(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t {
+	case repostore.ArtifactTypeReadme:
+		v, err := client.GetReadme(ctx, repo.Owner, repo.Name)
+		return []byte(v), err
+	case repostore.ArtifactTypeLanguages:
+		v, err := client.GetLanguages(ctx, repo.Owner, repo.Name)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(v)
+	case repostore.ArtifactTypeLatestRelease:
+		v, err := client.GetLatestRelease(ctx, repo.Owner, repo.Name)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(v)
+	case repostore.ArtifactTypeCodeOwners:
+		v, err := client.GetCodeOwners(ctx, repo.Owner, repo.Name)
+		return []byte(v), err
+	case repostore.ArtifactTypeLicense:
+		v, err := client.GetLicense(ctx, repo.Owner, repo.Name)
+		return []byte(v), err
+	default:
+		return nil, fmt.Errorf("unsupported artifact type: %s", t)
+	}
+}
@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/hay-kot/repomgr/app/core/config"
+	"github.com/hay-kot/repomgr/app/repos"
+	"github.com/matryer/is"
+)
+
+func Test_Controller_repoSource(t *testing.T) {
+	type tcase struct {
+		name    string
+		repo    repos.Repository
+		want    string
+		wantErr bool
+	}
+
+	cases := []tcase{
+		{
+			name: "org repository synced by a user",
+			repo: repos.Repository{Owner: "acme", Name: "infra", Source: "github/hay-kot"},
+			want: "github/hay-kot",
+		},
+		{
+			name: "source differs from the owner",
+			repo: repos.Repository{Owner: "hay-kot", Name: "repomgr", Source: "github/acme"},
+			want: "github/acme",
+		},
+		{
+			name:    "source not configured",
+			repo:    repos.Repository{Owner: "hay-kot", Name: "repomgr", Source: "github/removed"},
+			wantErr: true,
+		},
+		{
+			name: "no source matches the owner",
+			repo: repos.Repository{Owner: "acme", Name: "infra"},
+			want: "github/acme",
+		},
+		{
+			name: "no source falls back to the first",
+			repo: repos.Repository{Owner: "other", Name: "infra"},
+			want: "github/hay-kot",
+		},
+	}
+
+	ctrl := &Controller{
+		conf: &config.Config{
+			Sources: []config.Source{
+				{Type: config.SourceTypeGithub, Username: "hay-kot"},
+				{Type: config.SourceTypeGithub, Username: "acme"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			got, err := ctrl.repoSource(tc.repo)
+			if tc.wantErr {
+				is.True(err != nil)
+				return
+			}
+
+			is.NoErr(err)
+			is.Equal(got.ID(), tc.want)
+		})
+	}
+}
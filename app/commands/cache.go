@@ -2,72 +2,423 @@ package commands
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"os"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/hay-kot/repomgr/app/commands/ui"
+	"github.com/hay-kot/repomgr/app/console"
 	"github.com/hay-kot/repomgr/app/core/config"
+	"github.com/hay-kot/repomgr/app/core/daemon"
+	"github.com/hay-kot/repomgr/app/core/repostore"
 	"github.com/hay-kot/repomgr/app/repos"
+	"github.com/rs/zerolog/log"
 	"github.com/sourcegraph/conc/pool"
 )
 
-func (ctrl *Controller) Cache(ctx context.Context) error {
-	return ui.NewSpinnerFunc("cacheing repositories...", func(msgch chan<- string) error {
-		wg := pool.New().
-			WithMaxGoroutines(ctrl.conf.Concurrency).
-			WithErrors().
-			WithContext(ctx)
-
-		total := 0
-		appendTotal := func(v int) {
-			total += v
-			msgch <- fmt.Sprintf("total repositories: %d", total)
-		}
+// FormatText is the human readable progress output of the cache sync.
+const FormatText = "text"
 
-		collectionch := make(chan []repos.Repository, 1)
+// SyncEventKind describes the step of the sync a SyncEvent reports.
+type SyncEventKind string
 
-		for i := range ctrl.conf.Sources {
-			source := ctrl.conf.Sources[i]
-			wg.Go(func(ctx context.Context) error {
-				client, err := ctrl.client(source.Type, source.Token())
-				if err != nil {
-					return err
-				}
+const (
+	// SyncSourceStarted is sent before the repositories of a source are fetched.
+	SyncSourceStarted SyncEventKind = "source_started"
+	// SyncPageFetched is sent for every page of repositories fetched from a
+	// source, Count is the number of repositories on the page.
+	SyncPageFetched SyncEventKind = "page_fetched"
+	// SyncSourceFinished is sent once all repositories of a source are fetched.
+	SyncSourceFinished SyncEventKind = "source_finished"
+	// SyncReposUpserted is sent once the repositories are saved to the cache.
+	SyncReposUpserted SyncEventKind = "repos_upserted"
+	// SyncArtifactsStarted is sent before the artifacts are refreshed.
+	SyncArtifactsStarted SyncEventKind = "artifacts_started"
+	// SyncArtifactsRefreshed is sent once the artifacts are refreshed, Error is
+	// set if some artifacts failed to refresh.
+	SyncArtifactsRefreshed SyncEventKind = "artifacts_refreshed"
+	// SyncError is sent when a source fails to sync or the repositories cannot
+	// be saved.
+	SyncError SyncEventKind = "error"
+	// SyncSummary is the last event of a sync, Failed is the number of sources
+	// that failed and Error is set if the sync failed.
+	SyncSummary SyncEventKind = "summary"
+)
 
-				repos, err := client.GetAllByUsername(ctx, source.Username)
-				if err != nil {
-					return err
-				}
+// SyncEvent reports the progress of a cache sync. Message is the human readable
+// form of the event shown by the spinner.
+type SyncEvent struct {
+	Time       time.Time     `json:"time"`
+	Kind       SyncEventKind `json:"event"`
+	Source     string        `json:"source,omitempty"`
+	Page       int           `json:"page,omitempty"`
+	Count      int           `json:"count"`
+	Failed     int           `json:"failed,omitempty"`
+	DurationMS int64         `json:"duration_ms,omitempty"`
+	Error      string        `json:"error,omitempty"`
+	Message    string        `json:"message"`
 
-				collectionch <- repos
+	// Diff is set on the summary of a dry run.
+	Diff *SyncDiffOutput `json:"diff,omitempty"`
+}
 
-				appendTotal(len(repos))
-				return nil
+// CacheOptions are the options for the cache sync.
+type CacheOptions struct {
+	// Output is the progress output, FormatText or FormatJSON.
+	Output string
+	// Strict fails the sync if any source fails, otherwise the sync only fails
+	// if every source fails.
+	Strict bool
+	// DryRun fetches the sources and reports the changes to the cache without
+	// saving them.
+	DryRun bool
+}
+
+// Cache syncs the repositories of every source to the cache and refreshes the
+// artifacts. The result is recorded in the daemon status file. The progress is
+// shown with a spinner if w is a terminal and written as plain lines otherwise,
+// or written as JSON lines of SyncEvents for the json output. Sources are
+// synced independently, a failing source does not prevent the repositories of
+// the other sources from being saved.
+func (ctrl *Controller) Cache(ctx context.Context, w io.Writer, opts CacheOptions) error {
+	var diff *repostore.SyncDiff
+
+	run := func(emit func(SyncEvent)) (syncResult, error) {
+		if !opts.DryRun {
+			return ctrl.cacheSync(ctx, opts.Strict, emit)
+		}
+
+		result, d, err := ctrl.cacheDiff(ctx, opts.Strict, emit)
+		diff = &d
+		return result, err
+	}
+
+	var (
+		result syncResult
+		err    error
+	)
+
+	switch opts.Output {
+	case "", FormatText:
+		if isTerminal(w) {
+			err = ui.NewSpinnerFunc("cacheing repositories...", func(msgch chan<- string) error {
+				result, err = run(func(ev SyncEvent) { msgch <- ev.Message })
+				return err
+			})
+		} else {
+			var mu sync.Mutex
+			result, err = run(func(ev SyncEvent) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				fmt.Fprintln(w, ev.Message)
 			})
 		}
 
-		items := make([]repos.Repository, 0, total)
-		colwg := pool.New()
-		colwg.Go(func() {
-			for repos := range collectionch {
-				items = append(items, repos...)
+		if len(result.sources) > 0 {
+			ctrl.cons.LineBreak()
+			ctrl.cons.List("Sources", result.listItems())
+		}
+
+		if err == nil && diff != nil {
+			ctrl.cons.LineBreak()
+			ctrl.printSyncDiff(*diff)
+		}
+
+		return err
+	case FormatJSON:
+		var (
+			mu  sync.Mutex
+			enc = json.NewEncoder(w)
+		)
+
+		_, err = run(func(ev SyncEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			err := enc.Encode(ev)
+			if err != nil {
+				log.Error().Err(err).Msg("failed to write sync event")
 			}
 		})
+		return err
+	default:
+		return fmt.Errorf("unsupported output: %s", opts.Output)
+	}
+}
+
+// cacheSync runs the sync, records the result in the status file and emits the
+// summary event. If strict is set, failed sources fail the sync.
+func (ctrl *Controller) cacheSync(ctx context.Context, strict bool, emit func(SyncEvent)) (syncResult, error) {
+	start := time.Now()
+	result, err := ctrl.sync(ctx, emit, nil)
+	ctrl.updateStatus(func(s *daemon.Status) {
+		recordSync(s, start, result, err)
+	})
+
+	failed := len(result.failed())
+	elapsed := time.Since(start).Round(time.Millisecond)
+	summary := SyncEvent{
+		Time:       time.Now(),
+		Kind:       SyncSummary,
+		Count:      result.repositories,
+		Failed:     failed,
+		DurationMS: elapsed.Milliseconds(),
+		Message:    fmt.Sprintf("synced %d repositories in %s", result.repositories, elapsed),
+	}
 
-		err := wg.Wait()
-		if err != nil {
-			return err
+	if failed > 0 {
+		summary.Message += fmt.Sprintf(", %d sources failed", failed)
+	}
+
+	if err != nil {
+		summary.Message = fmt.Sprintf("sync failed after %s", elapsed)
+	} else if strict && failed > 0 {
+		// the repositories of the other sources are saved
+		err = fmt.Errorf("%d of %d sources failed to sync", failed, len(result.sources))
+	}
+
+	if err != nil {
+		summary.Error = err.Error()
+	}
+
+	emit(summary)
+	return result, err
+}
+
+// sourceResult is the outcome of syncing a single source.
+type sourceResult struct {
+	source string
+	count  int
+	err    error
+}
+
+// syncResult is the outcome of a sync. The repositories of the sources that
+// succeeded are saved even if other sources failed.
+type syncResult struct {
+	repositories int
+	sources      []sourceResult
+}
+
+// failed returns the sources that failed to sync.
+func (r syncResult) failed() []sourceResult {
+	failed := make([]sourceResult, 0, len(r.sources))
+	for _, s := range r.sources {
+		if s.err != nil {
+			failed = append(failed, s)
+		}
+	}
+
+	return failed
+}
+
+// err returns the errors of the failed sources joined, nil if every source
+// succeeded.
+func (r syncResult) err() error {
+	errs := make([]error, 0, len(r.sources))
+	for _, s := range r.failed() {
+		errs = append(errs, fmt.Errorf("%s: %w", s.source, s.err))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r syncResult) listItems() []console.ListItem {
+	items := make([]console.ListItem, len(r.sources))
+	for i, s := range r.sources {
+		if s.err != nil {
+			items[i] = console.ListItem{StatusOk: false, Status: fmt.Sprintf("%s: %s", s.source, s.err)}
+			continue
 		}
 
-		close(collectionch)
-		colwg.Wait()
+		items[i] = console.ListItem{StatusOk: true, Status: fmt.Sprintf("%s: %d repositories", s.source, s.count)}
+	}
+
+	return items
+}
 
-		msgch <- fmt.Sprintf("total repositories: %d", len(items))
-		msgch <- "saving repositories to database..."
-		msgch <- fmt.Sprintf("total cached: %d", len(items))
+// sync fetches the repositories of every source, saves them to the cache and
+// refreshes the artifacts, reporting the progress as events to emit. emit is
+// called from multiple goroutines. If saved is not nil it is called once the
+// repositories are saved, before the artifacts are refreshed.
+//
+// A source that fails is reported in the result and does not stop the other
+// sources, an error is only returned if every source failed, the context was
+// cancelled or the repositories could not be saved.
+func (ctrl *Controller) sync(ctx context.Context, emit func(SyncEvent), saved func()) (syncResult, error) {
+	send := func(ev SyncEvent) {
+		ev.Time = time.Now()
+		emit(ev)
+	}
 
-		return ctrl.store.UpsertMany(ctx, items)
+	result, items := ctrl.fetchAll(ctx, send)
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+
+	if len(result.failed()) == len(result.sources) {
+		return result, result.err()
+	}
+
+	err := ctrl.store.UpsertMany(ctx, items)
+	if err != nil {
+		send(SyncEvent{
+			Kind:    SyncError,
+			Error:   err.Error(),
+			Message: fmt.Sprintf("failed to save repositories: %s", err),
+		})
+		return result, err
+	}
+
+	result.repositories = len(items)
+	send(SyncEvent{
+		Kind:    SyncReposUpserted,
+		Count:   len(items),
+		Message: fmt.Sprintf("total cached: %d", len(items)),
+	})
+
+	if saved != nil {
+		saved()
+	}
+
+	if len(ctrl.conf.Artifacts.Types) == 0 {
+		return result, nil
+	}
+
+	send(SyncEvent{Kind: SyncArtifactsStarted, Message: "refreshing artifacts..."})
+	n, err := ctrl.refresher().Refresh(ctx)
+
+	refreshed := SyncEvent{
+		Kind:    SyncArtifactsRefreshed,
+		Count:   n,
+		Message: fmt.Sprintf("total artifacts refreshed: %d", n),
+	}
+
+	if err != nil {
+		// artifacts are best-effort enrichment data and should not fail the sync
+		log.Warn().Err(err).Msg("failed to refresh some artifacts")
+		refreshed.Error = err.Error()
+	}
+
+	send(refreshed)
+	return result, nil
+}
+
+// fetchAll fetches the repositories of every source concurrently. Sources
+// that fail are reported in the result, the repositories of the other sources
+// are returned.
+func (ctrl *Controller) fetchAll(ctx context.Context, send func(SyncEvent)) (syncResult, []repos.Repository) {
+	var (
+		mu      sync.Mutex
+		total   = 0
+		results = make([]sourceResult, len(ctrl.conf.Sources))
+		fetched = make([][]repos.Repository, len(ctrl.conf.Sources))
+	)
+
+	appendTotal := func(source string, v int) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		total += v
+		send(SyncEvent{
+			Kind:    SyncSourceFinished,
+			Source:  source,
+			Count:   v,
+			Message: fmt.Sprintf("total repositories: %d", total),
+		})
+	}
+
+	p := pool.New().
+		WithMaxGoroutines(ctrl.conf.Concurrency).
+		WithContext(ctx)
+
+	for i, source := range ctrl.conf.Sources {
+		p.Go(func(ctx context.Context) error {
+			id := source.ID()
+			results[i].source = id
+
+			send(SyncEvent{
+				Kind:    SyncSourceStarted,
+				Source:  id,
+				Message: fmt.Sprintf("fetching repositories of %s...", id),
+			})
+
+			items, err := ctrl.fetchSource(ctx, source, send)
+			if err != nil {
+				results[i].err = err
+				send(SyncEvent{
+					Kind:    SyncError,
+					Source:  id,
+					Error:   err.Error(),
+					Message: fmt.Sprintf("failed to sync %s: %s", id, err),
+				})
+				return nil
+			}
+
+			results[i].count = len(items)
+			fetched[i] = items
+
+			appendTotal(id, len(items))
+			return nil
+		})
+	}
+
+	_ = p.Wait()
+
+	return syncResult{sources: results}, slices.Concat(fetched...)
+}
+
+// fetchSource fetches the repositories of the source, sending an event for
+// every page fetched.
+func (ctrl *Controller) fetchSource(ctx context.Context, source config.Source, send func(SyncEvent)) ([]repos.Repository, error) {
+	id := source.ID()
+
+	client, err := ctrl.client(source.Type, source.Token())
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := client.GetAllByUsername(ctx, source.Username, func(page, count int) {
+		send(SyncEvent{
+			Kind:    SyncPageFetched,
+			Source:  id,
+			Page:    page,
+			Count:   count,
+			Message: fmt.Sprintf("fetched page %d of %s (%d repositories)", page, id, count),
+		})
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range items {
+		items[i].Source = id
+	}
+
+	return items, nil
+}
+
+// logSyncEvent writes the sync event to the debug log, used by the syncs that
+// run in the background.
+func logSyncEvent(ev SyncEvent) {
+	log.Debug().Str("event", string(ev.Kind)).Msg(ev.Message)
+}
+
+// isTerminal returns true if w is a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
 }
 
 func (ctrl *Controller) client(t config.SourceType, token string) (repos.RepositoryClient, error) {
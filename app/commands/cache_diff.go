@@ -0,0 +1,164 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hay-kot/repomgr/app/console"
+	"github.com/hay-kot/repomgr/app/core/repostore"
+	"github.com/hay-kot/repomgr/app/repos"
+)
+
+// SyncDiffOutput is the JSON form of the changes a dry run sync would make to
+// the cache.
+type SyncDiffOutput struct {
+	Added   []string         `json:"added"`
+	Missing []string         `json:"missing"`
+	Changed []SyncDiffChange `json:"changed"`
+}
+
+// SyncDiffChange is a single change of a cached repository, Change is either a
+// change kind of the change log or the name of the changed field.
+type SyncDiffChange struct {
+	Repository string `json:"repository"`
+	Change     string `json:"change"`
+	Old        string `json:"old,omitempty"`
+	New        string `json:"new,omitempty"`
+}
+
+func newSyncDiffOutput(d repostore.SyncDiff) *SyncDiffOutput {
+	out := &SyncDiffOutput{
+		Added:   make([]string, len(d.Added)),
+		Missing: make([]string, len(d.Missing)),
+		Changed: []SyncDiffChange{},
+	}
+
+	for i, repo := range d.Added {
+		out.Added[i] = repo.DisplayName()
+	}
+
+	for i, repo := range d.Missing {
+		out.Missing[i] = repo.DisplayName()
+	}
+
+	for _, c := range d.Changed {
+		name := c.Next.DisplayName()
+		for _, change := range c.Changes {
+			out.Changed = append(out.Changed, SyncDiffChange{
+				Repository: name,
+				Change:     change.Kind.String(),
+				Old:        change.Old,
+				New:        change.New,
+			})
+		}
+
+		for _, f := range c.Fields {
+			out.Changed = append(out.Changed, SyncDiffChange{
+				Repository: name,
+				Change:     f.Field,
+				Old:        f.Old,
+				New:        f.New,
+			})
+		}
+	}
+
+	return out
+}
+
+// cacheDiff fetches the repositories of every source and compares them to the
+// cache without saving them. The repositories of sources that failed are not
+// reported as missing. If strict is set, failed sources fail the dry run.
+func (ctrl *Controller) cacheDiff(ctx context.Context, strict bool, emit func(SyncEvent)) (syncResult, repostore.SyncDiff, error) {
+	send := func(ev SyncEvent) {
+		ev.Time = time.Now()
+		emit(ev)
+	}
+
+	start := time.Now()
+	result, fetched := ctrl.fetchAll(ctx, send)
+
+	diff, err := ctrl.diffFetched(ctx, result, fetched)
+	if err == nil && strict && len(result.failed()) > 0 {
+		err = fmt.Errorf("%d of %d sources failed to sync", len(result.failed()), len(result.sources))
+	}
+
+	elapsed := time.Since(start).Round(time.Millisecond)
+	summary := SyncEvent{
+		Kind:       SyncSummary,
+		Count:      len(fetched),
+		Failed:     len(result.failed()),
+		DurationMS: elapsed.Milliseconds(),
+	}
+
+	if err != nil {
+		summary.Error = err.Error()
+		summary.Message = fmt.Sprintf("dry run failed after %s", elapsed)
+	} else {
+		summary.Diff = newSyncDiffOutput(diff)
+		summary.Message = fmt.Sprintf(
+			"dry run: %d added, %d changed, %d not returned by their source, no changes were made",
+			len(diff.Added), len(diff.Changed), len(diff.Missing),
+		)
+	}
+
+	send(summary)
+	return result, diff, err
+}
+
+// diffFetched compares the fetched repositories to the cache, the cached
+// repositories of the failed sources are not reported as missing.
+func (ctrl *Controller) diffFetched(ctx context.Context, result syncResult, fetched []repos.Repository) (repostore.SyncDiff, error) {
+	if err := ctx.Err(); err != nil {
+		return repostore.SyncDiff{}, err
+	}
+
+	failed := result.failed()
+	if len(failed) == len(result.sources) {
+		return repostore.SyncDiff{}, result.err()
+	}
+
+	incomplete := make([]string, len(failed))
+	for i, s := range failed {
+		incomplete[i] = s.source
+	}
+
+	return ctrl.store.DiffSync(ctx, fetched, incomplete)
+}
+
+// printSyncDiff prints the changes of a dry run sync as a table.
+func (ctrl *Controller) printSyncDiff(d repostore.SyncDiff) {
+	rows := make([][]string, 0, len(d.Added)+len(d.Missing)+len(d.Changed))
+	for _, repo := range d.Added {
+		rows = append(rows, []string{repo.DisplayName(), "added", "from " + repo.Source})
+	}
+
+	for _, c := range d.Changed {
+		name := c.Next.DisplayName()
+		for _, change := range c.Changes {
+			rows = append(rows, []string{name, change.Kind.String(), describeChange(change)})
+		}
+
+		for _, f := range c.Fields {
+			rows = append(rows, []string{name, f.Field, fmt.Sprintf("%q -> %q", f.Old, f.New)})
+		}
+	}
+
+	for _, repo := range d.Missing {
+		rows = append(rows, []string{repo.DisplayName(), "missing", "not returned by " + repo.Source + " (kept in cache)"})
+	}
+
+	status := "no changes were made"
+	if d.IsEmpty() {
+		status = "the cache is up to date, " + status
+	}
+
+	if len(rows) > 0 {
+		ctrl.cons.Table([]string{"repository", "change", "details"}, rows)
+		ctrl.cons.LineBreak()
+	}
+
+	ctrl.cons.List("Dry Run", []console.ListItem{
+		{StatusOk: true, Status: status},
+	})
+}
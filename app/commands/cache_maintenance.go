@@ -0,0 +1,228 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/hay-kot/repomgr/app/console"
+	"github.com/hay-kot/repomgr/app/repos"
+)
+
+// MaintenanceOptions are the shared options for the cache maintenance commands.
+type MaintenanceOptions struct {
+	// DryRun prints the changes that would be made without applying them.
+	DryRun bool
+	// Yes skips the confirmation prompt.
+	Yes bool
+}
+
+// confirm returns true if the changes should be applied, printing the dry-run
+// notice or prompting the user as required.
+func (ctrl *Controller) confirm(opts MaintenanceOptions, question string) bool {
+	if opts.DryRun {
+		ctrl.cons.LineBreak()
+		ctrl.cons.List("Dry Run", []console.ListItem{
+			{StatusOk: true, Status: "no changes were made"},
+		})
+		return false
+	}
+
+	if opts.Yes {
+		return true
+	}
+
+	return ctrl.cons.Confirm(question)
+}
+
+func (ctrl *Controller) removeRepositories(ctx context.Context, title string, items []repos.Repository, opts MaintenanceOptions) error {
+	if len(items) == 0 {
+		ctrl.cons.List(title, []console.ListItem{
+			{StatusOk: true, Status: "no repositories to remove"},
+		})
+		return nil
+	}
+
+	listItems := make([]console.ListItem, len(items))
+	ids := make([]int, len(items))
+	for i, repo := range items {
+		ids[i] = repo.ID
+		listItems[i] = console.ListItem{StatusOk: true, Status: repo.DisplayName()}
+	}
+
+	ctrl.cons.List(fmt.Sprintf("%s (%d repositories)", title, len(items)), listItems)
+
+	if !ctrl.confirm(opts, fmt.Sprintf("Remove %d repositories from the cache?", len(items))) {
+		return nil
+	}
+
+	err := ctrl.store.DeleteRepositories(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	ctrl.cons.List("Removed", []console.ListItem{
+		{StatusOk: true, Status: fmt.Sprintf("removed %d repositories", len(items))},
+	})
+	return nil
+}
+
+// CacheClear removes all repositories from the cache. If source is not empty,
+// only the repositories synced from that source are removed.
+func (ctrl *Controller) CacheClear(ctx context.Context, source string, opts MaintenanceOptions) error {
+	var (
+		items []repos.Repository
+		err   error
+	)
+
+	if source == "" {
+		items, err = ctrl.store.GetAll(ctx)
+	} else {
+		id := ""
+		for _, s := range ctrl.conf.Sources {
+			if s.Matches(source) {
+				id = s.ID()
+				break
+			}
+		}
+
+		if id == "" {
+			return fmt.Errorf("no source configured matching '%s'", source)
+		}
+
+		items, err = ctrl.store.GetBySource(ctx, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	return ctrl.removeRepositories(ctx, "Clear Cache", items, opts)
+}
+
+// CachePrune removes repositories that have not been seen in a sync for longer
+// than the stale duration.
+func (ctrl *Controller) CachePrune(ctx context.Context, stale time.Duration, opts MaintenanceOptions) error {
+	if stale <= 0 {
+		return fmt.Errorf("stale duration must be greater than 0")
+	}
+
+	items, err := ctrl.store.GetSyncedBefore(ctx, time.Now().Add(-stale))
+	if err != nil {
+		return err
+	}
+
+	return ctrl.removeRepositories(ctx, "Prune Stale Repositories", items, opts)
+}
+
+// CacheVacuum rebuilds the database file to reclaim unused space.
+func (ctrl *Controller) CacheVacuum(ctx context.Context, opts MaintenanceOptions) error {
+	before, err := ctrl.databaseSize()
+	if err != nil {
+		return err
+	}
+
+	ctrl.cons.List("Vacuum", []console.ListItem{
+		{StatusOk: true, Status: fmt.Sprintf("database: %s", ctrl.conf.Database.File)},
+		{StatusOk: true, Status: fmt.Sprintf("current size: %s", humanize.Bytes(before))},
+	})
+
+	if !ctrl.confirm(opts, "Vacuum the database?") {
+		return nil
+	}
+
+	err = ctrl.store.Vacuum(ctx)
+	if err != nil {
+		return err
+	}
+
+	after, err := ctrl.databaseSize()
+	if err != nil {
+		return err
+	}
+
+	ctrl.cons.List("Vacuumed", []console.ListItem{
+		{StatusOk: true, Status: fmt.Sprintf("new size: %s", humanize.Bytes(after))},
+	})
+	return nil
+}
+
+// CacheVerify checks the integrity of the database and reports orphaned rows,
+// offering to remove them.
+func (ctrl *Controller) CacheVerify(ctx context.Context, opts MaintenanceOptions) error {
+	report, err := ctrl.store.Verify(ctx)
+	if err != nil {
+		return err
+	}
+
+	items := []console.ListItem{}
+	if len(report.Integrity) == 0 {
+		items = append(items, console.ListItem{StatusOk: true, Status: "integrity check passed"})
+	}
+
+	for _, line := range report.Integrity {
+		items = append(items, console.ListItem{StatusOk: false, Status: line})
+	}
+
+	orphans := []struct {
+		name  string
+		count int64
+	}{
+		{"artifacts", report.OrphanedArtifacts},
+		{"tags", report.OrphanedTags},
+		{"notes", report.OrphanedNotes},
+		{"pins", report.OrphanedPins},
+		{"changes", report.OrphanedChanges},
+	}
+
+	for _, o := range orphans {
+		items = append(items, console.ListItem{
+			StatusOk: o.count == 0,
+			Status:   fmt.Sprintf("%d orphaned %s", o.count, o.name),
+		})
+	}
+
+	ctrl.cons.List("Verify Cache", items)
+
+	if len(report.Integrity) > 0 {
+		return fmt.Errorf("database failed integrity check")
+	}
+
+	if report.Orphans() == 0 {
+		return nil
+	}
+
+	if !ctrl.confirm(opts, fmt.Sprintf("Remove %d orphaned rows?", report.Orphans())) {
+		return nil
+	}
+
+	n, err := ctrl.store.DeleteOrphans(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctrl.cons.List("Removed", []console.ListItem{
+		{StatusOk: true, Status: fmt.Sprintf("removed %d orphaned rows", n)},
+	})
+	return nil
+}
+
+// databaseSize returns the size of the database file including the write-ahead
+// log if present.
+func (ctrl *Controller) databaseSize() (uint64, error) {
+	var total uint64
+	for _, path := range []string{ctrl.conf.Database.File, ctrl.conf.Database.File + "-wal"} {
+		info, err := os.Stat(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return 0, err
+		}
+
+		total += uint64(info.Size())
+	}
+
+	return total, nil
+}
@@ -0,0 +1,205 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/hay-kot/repomgr/app/console"
+	"github.com/hay-kot/repomgr/app/core/config"
+	"github.com/hay-kot/repomgr/app/core/repostore"
+	"github.com/hay-kot/repomgr/app/repos"
+	"github.com/matryer/is"
+	_ "modernc.org/sqlite"
+)
+
+// fakeClient returns the repositories or the error of the username, every
+// user has a single page of repositories.
+type fakeClient struct {
+	repos.RepositoryClient
+
+	byUser map[string][]repos.Repository
+	errs   map[string]error
+}
+
+func (c *fakeClient) GetAllByUsername(_ context.Context, username string, onPage repos.PageFunc) ([]repos.Repository, error) {
+	if err := c.errs[username]; err != nil {
+		return nil, err
+	}
+
+	items := c.byUser[username]
+	if onPage != nil {
+		onPage(1, len(items))
+	}
+
+	return items, nil
+}
+
+func tStore(t *testing.T) *repostore.RepoStore {
+	conn, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := repostore.New(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return store
+}
+
+// tSyncController returns a controller that syncs a github source for every
+// username with the client.
+func tSyncController(t *testing.T, client repos.RepositoryClient, usernames ...string) *Controller {
+	conf := &config.Config{
+		Concurrency: 2,
+		Daemon: config.Daemon{
+			StatusFile: filepath.Join(t.TempDir(), "daemon.json"),
+		},
+	}
+
+	for _, username := range usernames {
+		conf.Sources = append(conf.Sources, config.Source{Type: config.SourceTypeGithub, Username: username})
+	}
+
+	ctrl := &Controller{
+		conf:  conf,
+		store: tStore(t),
+		cc:    clientCache{cache: make(map[cacheKey]repos.RepositoryClient)},
+	}
+
+	ctrl.cc.set(config.SourceTypeGithub, "", client)
+	return ctrl
+}
+
+func Test_Controller_cacheSync_FailedSource(t *testing.T) {
+	type tcase struct {
+		name      string
+		strict    bool
+		sources   []string
+		wantErr   bool
+		wantRepos int
+		wantItems []console.ListItem
+	}
+
+	cases := []tcase{
+		{
+			name:      "failed source",
+			sources:   []string{"hay-kot", "acme"},
+			wantRepos: 2,
+			wantItems: []console.ListItem{
+				{StatusOk: true, Status: "github/hay-kot: 2 repositories"},
+				{StatusOk: false, Status: "github/acme: rate limited"},
+			},
+		},
+		{
+			name:      "failed source strict",
+			strict:    true,
+			sources:   []string{"hay-kot", "acme"},
+			wantErr:   true,
+			wantRepos: 2, // saved even though the sync fails
+			wantItems: []console.ListItem{
+				{StatusOk: true, Status: "github/hay-kot: 2 repositories"},
+				{StatusOk: false, Status: "github/acme: rate limited"},
+			},
+		},
+		{
+			name:      "every source failed",
+			sources:   []string{"acme"},
+			wantErr:   true,
+			wantRepos: 0,
+			wantItems: []console.ListItem{
+				{StatusOk: false, Status: "github/acme: rate limited"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			ctx := context.Background()
+
+			client := &fakeClient{
+				byUser: map[string][]repos.Repository{
+					"hay-kot": {
+						{RemoteID: "1", Owner: "hay-kot", Name: "repomgr"},
+						{RemoteID: "2", Owner: "hay-kot", Name: "go-web"},
+					},
+				},
+				errs: map[string]error{"acme": errors.New("rate limited")},
+			}
+
+			ctrl := tSyncController(t, client, tc.sources...)
+
+			result, err := ctrl.cacheSync(ctx, tc.strict, func(SyncEvent) {})
+			is.Equal(err != nil, tc.wantErr)
+			is.Equal(result.listItems(), tc.wantItems)
+
+			cached, err := ctrl.store.GetAll(ctx)
+			is.NoErr(err)
+			is.Equal(len(cached), tc.wantRepos)
+
+			for _, repo := range cached {
+				is.Equal(repo.Source, "github/hay-kot")
+			}
+		})
+	}
+}
+
+func Test_Controller_Cache_JSONEvents(t *testing.T) {
+	is := is.New(t)
+
+	client := &fakeClient{
+		byUser: map[string][]repos.Repository{
+			"hay-kot": {{RemoteID: "1", Owner: "hay-kot", Name: "repomgr"}},
+		},
+	}
+
+	ctrl := tSyncController(t, client, "hay-kot")
+
+	var buf bytes.Buffer
+	err := ctrl.Cache(context.Background(), &buf, CacheOptions{Output: FormatJSON})
+	is.NoErr(err)
+
+	// the fields every consumer can rely on, omitted empty fields such as
+	// duration_ms are not listed
+	want := []struct {
+		kind   string
+		fields []string
+	}{
+		{kind: "source_started", fields: []string{"count", "event", "message", "source", "time"}},
+		{kind: "page_fetched", fields: []string{"count", "event", "message", "page", "source", "time"}},
+		{kind: "source_finished", fields: []string{"count", "event", "message", "source", "time"}},
+		{kind: "repos_upserted", fields: []string{"count", "event", "message", "time"}},
+		{kind: "summary", fields: []string{"count", "event", "message", "time"}},
+	}
+
+	var got []map[string]any
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var ev map[string]any
+		is.NoErr(dec.Decode(&ev))
+		got = append(got, ev)
+	}
+
+	is.Equal(len(got), len(want))
+	for i, ev := range got {
+		is.Equal(ev["event"], want[i].kind)
+
+		for _, field := range want[i].fields {
+			_, ok := ev[field]
+			is.True(ok) // required field is missing
+		}
+
+		_, failed := ev["error"]
+		is.True(!failed)
+	}
+
+	summary := got[len(got)-1]
+	is.Equal(summary["count"], float64(1))
+}
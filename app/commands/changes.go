@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/hay-kot/repomgr/app/core/repostore"
+)
+
+// Changes prints the repository changes recorded since the given duration. If
+// kinds is not empty, only changes of those kinds are printed.
+func (ctrl *Controller) Changes(ctx context.Context, since time.Duration, kinds []string) error {
+	filter := make(map[repostore.ChangeKind]bool, len(kinds))
+	for _, k := range kinds {
+		kind := repostore.ChangeKind(k)
+		if err := kind.Validate(); err != nil {
+			return err
+		}
+
+		filter[kind] = true
+	}
+
+	changes, err := ctrl.store.ChangesSince(ctx, time.Now().Add(-since))
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(changes))
+	for _, c := range changes {
+		if len(filter) > 0 && !filter[c.Kind] {
+			continue
+		}
+
+		rows = append(rows, []string{
+			humanize.Time(c.CreatedAt),
+			c.DisplayName,
+			c.Kind.String(),
+			describeChange(c),
+		})
+	}
+
+	ctrl.cons.Table([]string{"when", "repository", "change", "details"}, rows)
+	return nil
+}
+
+func describeChange(c repostore.Change) string {
+	switch c.Kind {
+	case repostore.ChangeRenamed, repostore.ChangeTransferred, repostore.ChangeDescription:
+		return fmt.Sprintf("%q -> %q", c.Old, c.New)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/hay-kot/repomgr/app/console"
+	"github.com/hay-kot/repomgr/app/core/git"
+	"github.com/hay-kot/repomgr/app/repos"
+	"github.com/sourcegraph/conc/pool"
+)
+
+// CloneOptions are the options for cloning repositories.
+type CloneOptions struct {
+	// HTTPS clones using the HTTPS url instead of the SSH url.
+	HTTPS bool
+}
+
+type cloneResult struct {
+	repo    repos.Repository
+	dir     string
+	skipped bool
+	err     error
+}
+
+// Clone clones the repositories for the "owner/name" arguments and the
+// repositories matching the filter into the directory computed from the
+// clone directory matchers. Repositories that are already cloned are skipped.
+func (ctrl *Controller) Clone(ctx context.Context, names []string, f RepoFilter, opts CloneOptions) error {
+	if len(names) == 0 && f.IsZero() {
+		return fmt.Errorf("expected at least one repository or a filter")
+	}
+
+	items, err := ctrl.resolveRepos(ctx, names, f)
+	if err != nil {
+		return err
+	}
+
+	results := ctrl.cloneAll(ctx, items, opts, nil)
+	return ctrl.reportClones(results)
+}
+
+// cloneAll clones the repositories concurrently, bounded by the configured
+// concurrency, and returns a result for every repository in the same order.
+// If set, done is called after each clone finishes, one call at a time.
+func (ctrl *Controller) cloneAll(ctx context.Context, items []repos.Repository, opts CloneOptions, done func(cloneResult)) []cloneResult {
+	var (
+		results = make([]cloneResult, len(items))
+		mu      sync.Mutex
+	)
+
+	p := pool.New().WithMaxGoroutines(ctrl.conf.Concurrency)
+	for i, repo := range items {
+		p.Go(func() {
+			results[i] = ctrl.cloneOne(ctx, repo, opts)
+
+			if done != nil {
+				mu.Lock()
+				done(results[i])
+				mu.Unlock()
+			}
+		})
+	}
+	p.Wait()
+
+	return results
+}
+
+func (ctrl *Controller) cloneOne(ctx context.Context, repo repos.Repository, opts CloneOptions) cloneResult {
+	result := cloneResult{repo: repo}
+
+	dir, err := ctrl.rfs.FindCloneDirectory(repo)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	result.dir = dir
+
+	if ctrl.rfs.Refresh(repo) {
+		result.skipped = true
+		return result
+	}
+
+	url := repo.CloneSSHURL
+	if opts.HTTPS {
+		url = repo.CloneURL
+	}
+
+	result.err = git.Clone(ctx, url, dir)
+	ctrl.rfs.Refresh(repo)
+	return result
+}
+
+func (ctrl *Controller) reportClones(results []cloneResult) error {
+	var (
+		items  = make([]console.ListItem, 0, len(results))
+		failed = 0
+	)
+
+	for _, r := range results {
+		switch {
+		case r.err != nil:
+			failed++
+			items = append(items, console.ListItem{
+				StatusOk: false,
+				Status:   fmt.Sprintf("%s: %s", r.repo.DisplayName(), r.err),
+			})
+		case r.skipped:
+			items = append(items, console.ListItem{
+				StatusOk: true,
+				Status:   fmt.Sprintf("%s already cloned at %s", r.repo.DisplayName(), r.dir),
+			})
+		default:
+			items = append(items, console.ListItem{
+				StatusOk: true,
+				Status:   fmt.Sprintf("%s cloned to %s", r.repo.DisplayName(), r.dir),
+			})
+		}
+	}
+
+	ctrl.cons.List(fmt.Sprintf("Clone (%d/%d succeeded)", len(results)-failed, len(results)), items)
+
+	if failed > 0 {
+		return fmt.Errorf("failed to clone %d repositories", failed)
+	}
+
+	return nil
+}
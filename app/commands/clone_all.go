@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/hay-kot/repomgr/app/console"
+	"github.com/hay-kot/repomgr/app/repos"
+)
+
+// CloneAll clones every repository matching the filter that is not cloned yet
+// into the directory computed from the clone directory matchers. Progress is
+// written to w as each clone finishes. Already cloned repositories are
+// skipped, so an interrupted run is resumed by running it again.
+func (ctrl *Controller) CloneAll(ctx context.Context, w io.Writer, f RepoFilter, clone CloneOptions, opts MaintenanceOptions) error {
+	all, err := ctrl.filterRepos(ctx, f)
+	if err != nil {
+		return err
+	}
+
+	var (
+		pending = make([]repos.Repository, 0, len(all))
+		rows    = make([][]string, 0, len(all))
+	)
+
+	for _, repo := range all {
+		if ctrl.rfs.IsCloned(repo) {
+			continue
+		}
+
+		dir, err := ctrl.rfs.FindCloneDirectory(repo)
+		if err != nil {
+			return err
+		}
+
+		pending = append(pending, repo)
+		rows = append(rows, []string{repo.DisplayName(), dir})
+	}
+
+	if len(pending) == 0 {
+		ctrl.cons.List("Clone All", []console.ListItem{
+			{StatusOk: true, Status: fmt.Sprintf("all %d matching repositories are already cloned", len(all))},
+		})
+		return nil
+	}
+
+	ctrl.cons.Table([]string{"repository", "clone directory"}, rows)
+
+	question := fmt.Sprintf("Clone %d repositories (%d already cloned)?", len(pending), len(all)-len(pending))
+	if !ctrl.confirm(opts, question) {
+		return nil
+	}
+
+	finished := 0
+	results := ctrl.cloneAll(ctx, pending, clone, func(r cloneResult) {
+		finished++
+
+		status := "cloned"
+		if r.err != nil {
+			status = "failed"
+		}
+
+		fmt.Fprintf(w, " [%d/%d] %s %s\n", finished, len(pending), status, r.repo.DisplayName())
+	})
+
+	ctrl.cons.LineBreak()
+	return ctrl.reportClones(results)
+}
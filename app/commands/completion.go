@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// CompleteRepos writes the "owner/name" of every cached repository to the
+// writer, one per line, for shell completion.
+func (ctrl *Controller) CompleteRepos(ctx context.Context, w io.Writer) error {
+	r, err := ctrl.store.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, repo := range r {
+		_, err = fmt.Fprintln(w, repo.DisplayName())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CompleteBindings writes the configured key bindings to the writer, one per
+// line, for shell completion.
+func (ctrl *Controller) CompleteBindings(w io.Writer) error {
+	keys := make([]string, 0, len(ctrl.conf.KeyBindings))
+	for key := range ctrl.conf.KeyBindings {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		_, err := fmt.Fprintln(w, key)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
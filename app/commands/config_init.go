@@ -0,0 +1,215 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/hay-kot/repomgr/app/console"
+	"github.com/hay-kot/repomgr/app/core/config"
+)
+
+// token strategies offered by the config init wizard
+const (
+	tokenEnv = iota
+	tokenDotEnv
+	tokenInline
+	tokenNone
+)
+
+// ConfigInit interactively asks for the sources, token strategy, clone
+// directory layout and shell, and writes a commented configuration file to
+// path. An existing file is only overwritten after confirmation.
+//
+// ConfigInit is not a Controller method as it runs before a configuration
+// exists.
+func ConfigInit(cons *console.Console, path string) error {
+	_, err := os.Stat(path)
+	switch {
+	case err == nil:
+		overwrite, err := cons.ConfirmInput(fmt.Sprintf("config file %s already exists, overwrite?", path))
+		if err != nil {
+			return fmt.Errorf("config init aborted: %w", err)
+		}
+
+		if !overwrite {
+			return errors.New("aborted, config file was not written")
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return err
+	}
+
+	answers, tokenKey, err := askInit(cons)
+	if err != nil {
+		return fmt.Errorf("config init aborted: %w", err)
+	}
+
+	str, err := config.RenderInit(answers)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return err
+	}
+
+	// tokens may be stored in the file, so it is only readable by the user
+	err = os.WriteFile(path, []byte(str), 0o600)
+	if err != nil {
+		return err
+	}
+
+	items := []console.ListItem{
+		{StatusOk: true, Status: "config written to " + path},
+	}
+
+	if answers.DotEnv {
+		envpath := config.ExpandPath(path, config.DefaultDotEnvFile)
+
+		created, err := writeDotEnv(envpath, tokenKey[len("env:"):])
+		if err != nil {
+			return err
+		}
+
+		if created {
+			items = append(items, console.ListItem{
+				StatusOk: true,
+				Status:   "dotenv written to " + envpath + ", add your token to it",
+			})
+		}
+	}
+
+	cons.List("Config", items)
+	cons.LineBreak()
+	cons.List("Next Steps", []console.ListItem{
+		{StatusOk: true, Status: "run 'repomgr cache' to sync your repositories"},
+		{StatusOk: true, Status: "run 'repomgr shell-init <shell>' to cd into repositories from the search"},
+	})
+
+	return nil
+}
+
+// askInit asks the questions of the config init wizard, returning the answers
+// and the token key of the sources. An error is returned if the input is closed
+// before every question is answered.
+func askInit(cons *console.Console) (config.Init, string, error) {
+	answers := config.Init{}
+
+	// Sources
+	for {
+		username, err := cons.Prompt("GitHub username or organization to sync", "")
+		if err != nil {
+			return answers, "", err
+		}
+
+		if username != "" {
+			answers.Sources = append(answers.Sources, config.Source{
+				Type:     config.SourceTypeGithub,
+				Username: username,
+			})
+		}
+
+		if len(answers.Sources) == 0 {
+			continue
+		}
+
+		another, err := cons.ConfirmInput("add another source?")
+		if err != nil {
+			return answers, "", err
+		}
+
+		if !another {
+			break
+		}
+	}
+
+	// Token strategy
+	strategy, err := cons.Select("how should the GitHub token be provided?", []string{
+		"environment variable",
+		"dotenv file next to the config",
+		"stored in the config file",
+		"no token, only public repositories",
+	}, tokenEnv)
+	if err != nil {
+		return answers, "", err
+	}
+
+	var tokenKey string
+	switch strategy {
+	case tokenEnv, tokenDotEnv:
+		name, err := cons.Prompt("environment variable name", "GITHUB_TOKEN")
+		if err != nil {
+			return answers, "", err
+		}
+
+		tokenKey = "env:" + name
+		answers.DotEnv = strategy == tokenDotEnv
+	case tokenInline:
+		tokenKey, err = cons.Prompt("GitHub token", "")
+		if err != nil {
+			return answers, "", err
+		}
+	}
+
+	for i := range answers.Sources {
+		answers.Sources[i].TokenKey = tokenKey
+	}
+
+	// Clone directory layout
+	layouts := []string{
+		"~/src/{{ .Repo.Owner }}/{{ .Repo.Name }}",
+		"~/src/{{ .Repo.Name }}",
+		"custom",
+	}
+
+	layout, err := cons.Select("where should repositories be cloned?", layouts, 0)
+	if err != nil {
+		return answers, "", err
+	}
+
+	if layout == len(layouts)-1 {
+		answers.CloneDirectory, err = cons.Prompt("clone directory template", layouts[0])
+	} else {
+		answers.CloneDirectory = layouts[layout]
+	}
+	if err != nil {
+		return answers, "", err
+	}
+
+	// Shell
+	answers.Shell, err = cons.Prompt("shell used to run key binding commands", defaultShell())
+	if err != nil {
+		return answers, "", err
+	}
+
+	return answers, tokenKey, nil
+}
+
+// writeDotEnv creates the dotenv file with an empty value for the key if it does
+// not exist yet.
+func writeDotEnv(path, key string) (bool, error) {
+	_, err := os.Stat(path)
+	if err == nil {
+		return false, nil
+	}
+
+	if !errors.Is(err, os.ErrNotExist) {
+		return false, err
+	}
+
+	return true, os.WriteFile(path, []byte(key+"=\n"), 0o600)
+}
+
+// defaultShell returns the shell from the SHELL environment variable if it is
+// one of the common shells, falling back to bash.
+func defaultShell() string {
+	shell := filepath.Base(os.Getenv("SHELL"))
+	if slices.Contains([]string{"bash", "zsh", "fish", "sh"}, shell) {
+		return shell
+	}
+
+	return "bash"
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"sync"
 
+	"github.com/hay-kot/repomgr/app/console"
 	"github.com/hay-kot/repomgr/app/core/commander"
 	"github.com/hay-kot/repomgr/app/core/config"
 	"github.com/hay-kot/repomgr/app/core/repofs"
@@ -17,10 +18,11 @@ type Controller struct {
 	commander *commander.Commander
 	store     *repostore.RepoStore
 	conf      *config.Config
+	cons      *console.Console
 	cc        clientCache
 }
 
-func NewController(conf *config.Config, sqldb *sql.DB) (*Controller, error) {
+func NewController(conf *config.Config, sqldb *sql.DB, cons *console.Console) (*Controller, error) {
 	rfs := repofs.New(conf.CloneDirectories)
 
 	store, err := repostore.New(sqldb)
@@ -35,6 +37,7 @@ func NewController(conf *config.Config, sqldb *sql.DB) (*Controller, error) {
 
 	return &Controller{
 		conf:      conf,
+		cons:      cons,
 		store:     store,
 		rfs:       rfs,
 		commander: commander,
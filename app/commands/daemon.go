@@ -0,0 +1,195 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/hay-kot/repomgr/app/console"
+	"github.com/hay-kot/repomgr/app/core/daemon"
+	"github.com/hay-kot/repomgr/app/core/git"
+	"github.com/hay-kot/repomgr/app/repos"
+	"github.com/rs/zerolog/log"
+	"github.com/sourcegraph/conc/pool"
+)
+
+// DaemonOptions are the options for the background sync daemon.
+type DaemonOptions struct {
+	// Interval is the time between syncs.
+	Interval time.Duration
+	// Fetch fetches the remotes of every cloned repository after each sync.
+	Fetch bool
+}
+
+// Daemon syncs the cache every interval until the context is cancelled. The
+// lock file ensures a single daemon runs at a time and the status file is
+// updated after every sync.
+func (ctrl *Controller) Daemon(ctx context.Context, opts DaemonOptions) error {
+	if opts.Interval <= 0 {
+		return fmt.Errorf("interval must be greater than 0")
+	}
+
+	lock, err := daemon.Acquire(ctrl.conf.Daemon.LockFile)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		ctrl.updateStatus(func(s *daemon.Status) {
+			s.PID = 0
+			s.NextSync = time.Time{}
+		})
+
+		err := lock.Release()
+		if err != nil {
+			log.Warn().Err(err).Msg("failed to release daemon lock")
+		}
+	}()
+
+	ctrl.cons.List("Daemon", []console.ListItem{
+		{StatusOk: true, Status: fmt.Sprintf("pid: %d", os.Getpid())},
+		{StatusOk: true, Status: fmt.Sprintf("interval: %s", opts.Interval)},
+		{StatusOk: true, Status: fmt.Sprintf("status file: %s", ctrl.conf.Daemon.StatusFile)},
+	})
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		ctrl.daemonSync(ctx, opts)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// daemonSync runs a single sync of the daemon, fetching the clones if enabled,
+// and records the result in the status file. Failures are reported and do not
+// stop the daemon.
+func (ctrl *Controller) daemonSync(ctx context.Context, opts DaemonOptions) {
+	start := time.Now()
+	result, err := ctrl.sync(ctx, logSyncEvent, nil)
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	next := start.Add(opts.Interval)
+	ctrl.updateStatus(func(s *daemon.Status) {
+		recordSync(s, start, result, err)
+		s.PID = os.Getpid()
+		s.NextSync = next
+	})
+
+	items := result.listItems()
+	if err != nil {
+		log.Error().Err(err).Msg("daemon sync failed")
+		if len(result.failed()) == 0 {
+			items = append(items, console.ListItem{StatusOk: false, Status: fmt.Sprintf("sync failed: %s", err)})
+		}
+	} else {
+		items = append(items, console.ListItem{StatusOk: true, Status: fmt.Sprintf("synced %d repositories", result.repositories)})
+	}
+
+	if opts.Fetch {
+		fetched, failed := ctrl.fetchClones(ctx)
+		items = append(items, console.ListItem{
+			StatusOk: failed == 0,
+			Status:   fmt.Sprintf("fetched %d clones, %d failed", fetched, failed),
+		})
+	}
+
+	items = append(items, console.ListItem{StatusOk: true, Status: "next sync at " + next.Format("15:04")})
+
+	ctrl.cons.LineBreak()
+	ctrl.cons.List("Sync "+start.Format("2006-01-02 15:04"), items)
+}
+
+// fetchClones fetches the remotes of every cloned repository. It returns the
+// number of clones fetched and the number that failed, failures are logged.
+func (ctrl *Controller) fetchClones(ctx context.Context) (int, int) {
+	items, err := ctrl.store.GetAll(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to load repositories")
+		return 0, 0
+	}
+
+	p := pool.NewWithResults[bool]().WithMaxGoroutines(ctrl.conf.Concurrency)
+	for _, repo := range items {
+		if !ctrl.rfs.IsCloned(repo) {
+			continue
+		}
+
+		p.Go(func() bool {
+			err := ctrl.fetchClone(ctx, repo)
+			if err != nil {
+				log.Warn().Err(err).Str("repo", repo.DisplayName()).Msg("failed to fetch clone")
+				return false
+			}
+
+			return true
+		})
+	}
+
+	fetched, failed := 0, 0
+	for _, ok := range p.Wait() {
+		if ok {
+			fetched++
+		} else {
+			failed++
+		}
+	}
+
+	return fetched, failed
+}
+
+func (ctrl *Controller) fetchClone(ctx context.Context, repo repos.Repository) error {
+	dir, err := ctrl.rfs.FindCloneDirectory(repo)
+	if err != nil {
+		return err
+	}
+
+	return git.Fetch(ctx, dir)
+}
+
+// recordSync sets the result of the sync that started at start on the status.
+// A sync where only some sources failed counts as a sync, the errors of the
+// failed sources are recorded as the last error.
+func recordSync(s *daemon.Status, start time.Time, result syncResult, err error) {
+	if err != nil {
+		s.LastError = err.Error()
+		return
+	}
+
+	s.LastSync = start
+	s.LastError = ""
+	s.Repositories = result.repositories
+
+	if err := result.err(); err != nil {
+		s.LastError = err.Error()
+	}
+}
+
+// updateStatus applies the update to the status file. The status file is
+// informational, failures are logged and otherwise ignored.
+func (ctrl *Controller) updateStatus(update func(s *daemon.Status)) {
+	path := ctrl.conf.Daemon.StatusFile
+
+	status, err := daemon.ReadStatus(path)
+	if err != nil {
+		// a corrupt status file is replaced
+		log.Warn().Err(err).Str("path", path).Msg("failed to read daemon status")
+		status = daemon.Status{}
+	}
+
+	update(&status)
+
+	err = daemon.WriteStatus(path, status)
+	if err != nil {
+		log.Warn().Err(err).Str("path", path).Msg("failed to write daemon status")
+	}
+}
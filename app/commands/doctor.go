@@ -0,0 +1,235 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/hay-kot/repomgr/app/console"
+	"github.com/hay-kot/repomgr/app/core/commander"
+	"github.com/hay-kot/repomgr/app/core/config"
+	"github.com/hay-kot/repomgr/app/core/git"
+	"github.com/hay-kot/repomgr/app/core/repofs"
+	"github.com/hay-kot/repomgr/app/core/repostore"
+	"github.com/hay-kot/repomgr/app/repos"
+)
+
+// doctorRepo is the sample repository the key binding templates are rendered
+// against.
+var doctorRepo = repos.Repository{
+	ID:          1,
+	RemoteID:    "1",
+	Name:        "example",
+	Owner:       "octocat",
+	Description: "sample repository used by repomgr doctor",
+	HTMLURL:     "https://github.com/octocat/example",
+	CloneURL:    "https://github.com/octocat/example.git",
+	CloneSSHURL: "git@github.com:octocat/example.git",
+}
+
+// Doctor checks that the config loads, the source tokens resolve, the database
+// opens and migrates, the clone directories are writable, the shell and git
+// binaries exist and the key binding templates render, and prints the results
+// with hints for the failed checks. cfgErr is the error from reading the config
+// at path; no other checks are run when it is set. setupErr is the error from
+// creating the directories and opening the log file of a valid config.
+//
+// Doctor is not a Controller method as it must run when the config or the
+// database cannot be loaded.
+func Doctor(ctx context.Context, cons *console.Console, path string, conf *config.Config, cfgErr, setupErr error) error {
+	if cfgErr != nil {
+		cons.List("Doctor", []console.ListItem{{
+			StatusOk: false,
+			Status:   fmt.Sprintf("config %s: %s", path, cfgErr),
+			Hint:     "fix the config or run 'repomgr config init' to create a new one",
+		}})
+
+		return errors.New("config failed to load")
+	}
+
+	items := []console.ListItem{{StatusOk: true, Status: "config " + path + " loaded"}}
+	if setupErr != nil {
+		items = append(items, console.ListItem{
+			StatusOk: false,
+			Status:   fmt.Sprintf("environment: %s", setupErr),
+			Hint:     "check that the directories of 'database.file' and 'logs.file' can be created and are writable",
+		})
+	}
+	items = append(items, doctorSources(conf.Sources)...)
+	items = append(items, doctorDatabase(ctx, conf.Database))
+	items = append(items, doctorCloneDirectories(conf.CloneDirectories)...)
+	items = append(items,
+		doctorBinary("shell", conf.Shell, "install it or set 'shell' in the config to an installed shell"),
+		doctorBinary("git", git.Binary, "install git, it is required to clone and inspect repositories"),
+	)
+	items = append(items, doctorKeyBindings(conf)...)
+
+	cons.List("Doctor", items)
+
+	failed := 0
+	for _, item := range items {
+		if !item.StatusOk {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(items))
+	}
+
+	return nil
+}
+
+func doctorSources(sources []config.Source) []console.ListItem {
+	items := make([]console.ListItem, 0, len(sources))
+
+	for _, source := range sources {
+		item := console.ListItem{StatusOk: true}
+
+		env, isEnv := strings.CutPrefix(source.TokenKey, "env:")
+		switch {
+		case source.TokenKey == "":
+			item.Status = fmt.Sprintf("source %s has no token, only public repositories are synced", source.ID())
+		case source.Token() == "":
+			item.StatusOk = false
+			item.Status = fmt.Sprintf("source %s token from %s is empty", source.ID(), env)
+			item.Hint = fmt.Sprintf("export %s or add it to one of the dotenvs in the config", env)
+		case isEnv:
+			item.Status = fmt.Sprintf("source %s token resolved from %s", source.ID(), env)
+		default:
+			item.Status = fmt.Sprintf("source %s token set in the config", source.ID())
+		}
+
+		items = append(items, item)
+	}
+
+	return items
+}
+
+func doctorDatabase(ctx context.Context, conf config.Database) console.ListItem {
+	fail := func(err error) console.ListItem {
+		return console.ListItem{
+			StatusOk: false,
+			Status:   fmt.Sprintf("database %s: %s", conf.File, err),
+			Hint:     "check that the directory of 'database.file' exists and is writable",
+		}
+	}
+
+	sqldb, err := sql.Open("sqlite", conf.DNS())
+	if err != nil {
+		return fail(err)
+	}
+	defer sqldb.Close()
+
+	err = sqldb.PingContext(ctx)
+	if err != nil {
+		return fail(err)
+	}
+
+	// New applies the migrations
+	_, err = repostore.New(sqldb)
+	if err != nil {
+		return fail(err)
+	}
+
+	return console.ListItem{StatusOk: true, Status: "database " + conf.File + " opened and migrated"}
+}
+
+func doctorCloneDirectories(dirs repofs.CloneDirectories) []console.ListItem {
+	roots := dirs.Roots()
+	items := make([]console.ListItem, 0, len(roots))
+
+	for _, root := range roots {
+		info, err := os.Stat(root)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			items = append(items, console.ListItem{
+				StatusOk: false,
+				Status:   fmt.Sprintf("clone directory %s does not exist", root),
+				Hint:     fmt.Sprintf("create it with 'mkdir -p %s'", root),
+			})
+			continue
+		case err != nil:
+			items = append(items, console.ListItem{
+				StatusOk: false,
+				Status:   fmt.Sprintf("clone directory %s: %s", root, err),
+			})
+			continue
+		case !info.IsDir():
+			items = append(items, console.ListItem{
+				StatusOk: false,
+				Status:   fmt.Sprintf("clone directory %s is not a directory", root),
+				Hint:     "update 'clone_directories' in the config",
+			})
+			continue
+		}
+
+		f, err := os.CreateTemp(root, ".repomgr-doctor-*")
+		if err != nil {
+			items = append(items, console.ListItem{
+				StatusOk: false,
+				Status:   fmt.Sprintf("clone directory %s is not writable", root),
+				Hint:     "check the permissions of the directory",
+			})
+			continue
+		}
+
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+
+		items = append(items, console.ListItem{
+			StatusOk: true,
+			Status:   fmt.Sprintf("clone directory %s is writable", root),
+		})
+	}
+
+	return items
+}
+
+func doctorBinary(name, bin, hint string) console.ListItem {
+	p, err := exec.LookPath(bin)
+	if err != nil {
+		return console.ListItem{
+			StatusOk: false,
+			Status:   fmt.Sprintf("%s '%s' not found", name, bin),
+			Hint:     hint,
+		}
+	}
+
+	return console.ListItem{StatusOk: true, Status: fmt.Sprintf("%s found at %s", name, p)}
+}
+
+func doctorKeyBindings(conf *config.Config) []console.ListItem {
+	cmd := commander.New(conf.KeyBindings, repofs.New(conf.CloneDirectories), nil)
+
+	keys := make([]string, 0, len(conf.KeyBindings))
+	for key := range conf.KeyBindings {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	items := make([]console.ListItem, 0, len(keys))
+	for _, key := range keys {
+		_, err := cmd.RenderCommand(key, doctorRepo)
+		if err != nil {
+			items = append(items, console.ListItem{
+				StatusOk: false,
+				Status:   fmt.Sprintf("key binding %s: %s", key, err),
+				Hint:     "templates can use {{ .Repo }} fields and {{ .CloneDir }}",
+			})
+			continue
+		}
+
+		items = append(items, console.ListItem{
+			StatusOk: true,
+			Status:   fmt.Sprintf("key binding %s renders", key),
+		})
+	}
+
+	return items
+}
@@ -0,0 +1,181 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/hay-kot/repomgr/app/console"
+	"github.com/hay-kot/repomgr/app/repos"
+	"github.com/hay-kot/repomgr/internal/prefixwriter"
+	"github.com/sourcegraph/conc/pool"
+)
+
+type execResult struct {
+	repo     repos.Repository
+	exitCode int
+	err      error
+}
+
+// Exec runs the command in the clone directory of every cloned repository
+// matching the filter in parallel, using the configured shell. A single
+// argument is passed to the shell as is, so it may use pipes and other shell
+// syntax, multiple arguments are quoted so each is passed to the command
+// unchanged.
+// The output of each command is streamed to w with every line prefixed by the
+// repository name, followed by a summary of the exit codes. An error is
+// returned if any command fails.
+func (ctrl *Controller) Exec(ctx context.Context, w io.Writer, f RepoFilter, args []string) error {
+	command := shellJoin(args)
+	if strings.TrimSpace(command) == "" {
+		return errors.New("expected a command to run")
+	}
+
+	all, err := ctrl.filterRepos(ctx, f)
+	if err != nil {
+		return err
+	}
+
+	var (
+		cloned  = make([]repos.Repository, 0, len(all))
+		longest = 0
+	)
+
+	for _, repo := range all {
+		if !ctrl.rfs.IsCloned(repo) {
+			continue
+		}
+
+		cloned = append(cloned, repo)
+		longest = max(longest, len(repo.DisplayName()))
+	}
+
+	if len(cloned) == 0 {
+		return errors.New("no cloned repositories matched")
+	}
+
+	var (
+		results = make([]execResult, len(cloned))
+		mu      sync.Mutex
+	)
+
+	p := pool.New().WithMaxGoroutines(ctrl.conf.Concurrency)
+	for i, repo := range cloned {
+		p.Go(func() {
+			prefix := fmt.Sprintf("%-*s | ", longest, repo.DisplayName())
+			out := prefixwriter.New(w, &mu, prefix)
+
+			results[i] = ctrl.execOne(ctx, repo, command, out)
+			_ = out.Flush()
+		})
+	}
+	p.Wait()
+
+	var (
+		items  = make([]console.ListItem, len(results))
+		failed = 0
+	)
+
+	for i, r := range results {
+		switch {
+		case r.err != nil:
+			failed++
+			items[i] = console.ListItem{
+				StatusOk: false,
+				Status:   fmt.Sprintf("%s: %s", r.repo.DisplayName(), r.err),
+			}
+		case r.exitCode != 0:
+			failed++
+			items[i] = console.ListItem{
+				StatusOk: false,
+				Status:   fmt.Sprintf("%s exited with %d", r.repo.DisplayName(), r.exitCode),
+			}
+		default:
+			items[i] = console.ListItem{
+				StatusOk: true,
+				Status:   fmt.Sprintf("%s exited with 0", r.repo.DisplayName()),
+			}
+		}
+	}
+
+	ctrl.cons.LineBreak()
+	ctrl.cons.List(fmt.Sprintf("Exec (%d/%d succeeded)", len(results)-failed, len(results)), items)
+
+	if failed > 0 {
+		return fmt.Errorf("command failed in %d repositories", failed)
+	}
+
+	return nil
+}
+
+func (ctrl *Controller) execOne(ctx context.Context, repo repos.Repository, command string, out io.Writer) execResult {
+	result := execResult{repo: repo}
+
+	dir, err := ctrl.rfs.FindCloneDirectory(repo)
+	if err != nil {
+		result.err = err
+		return result
+	}
+
+	cmd := exec.CommandContext(ctx, ctrl.conf.Shell, ctrl.conf.ShellCmdFlag, command)
+	cmd.Dir = dir
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	err = cmd.Run()
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		result.exitCode = exitErr.ExitCode()
+	case err != nil:
+		result.err = err
+	}
+
+	return result
+}
+
+// shellJoin joins the arguments into a shell command. A single argument is
+// returned unchanged, otherwise every argument that contains characters with a
+// special meaning to the shell is single quoted.
+func shellJoin(args []string) string {
+	if len(args) == 1 {
+		return args[0]
+	}
+
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellQuote(arg)
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+// shellQuote single quotes the argument if required. The quoting works for
+// POSIX shells and fish.
+func shellQuote(arg string) string {
+	if arg == "" {
+		return "''"
+	}
+
+	safe := strings.IndexFunc(arg, func(r rune) bool {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return false
+		case strings.ContainsRune("-_./=:@%+,", r):
+			return false
+		default:
+			return true
+		}
+	}) == -1
+
+	if safe {
+		return arg
+	}
+
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
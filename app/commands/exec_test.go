@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hay-kot/repomgr/app/core/config"
+	"github.com/hay-kot/repomgr/app/core/repofs"
+	"github.com/hay-kot/repomgr/app/repos"
+	"github.com/matryer/is"
+)
+
+func Test_shellJoin(t *testing.T) {
+	type tcase struct {
+		name string
+		args []string
+		want string
+	}
+
+	cases := []tcase{
+		{name: "single argument is unchanged", args: []string{"git status | head -n 1"}, want: "git status | head -n 1"},
+		{name: "safe arguments", args: []string{"git", "log", "--oneline", "-n", "5"}, want: "git log --oneline -n 5"},
+		{name: "spaces", args: []string{"git", "commit", "-m", "fix typo"}, want: "git commit -m 'fix typo'"},
+		{name: "single quote", args: []string{"echo", "it's"}, want: `echo 'it'\''s'`},
+		{name: "empty argument", args: []string{"printf", ""}, want: "printf ''"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+			is.Equal(shellJoin(tc.args), tc.want)
+		})
+	}
+}
+
+func Test_Controller_execOne_QuotedArgument(t *testing.T) {
+	is := is.New(t)
+
+	root := t.TempDir()
+	is.NoErr(os.MkdirAll(filepath.Join(root, "repomgr"), 0o755))
+
+	ctrl := &Controller{
+		conf: &config.Config{Shell: "sh", ShellCmdFlag: "-c"},
+		rfs: repofs.New(repofs.CloneDirectories{
+			Default: filepath.Join(root, "{{ .Repo.Name }}"),
+		}),
+	}
+
+	var out bytes.Buffer
+	repo := repos.Repository{Owner: "hay-kot", Name: "repomgr"}
+	command := shellJoin([]string{"printf", "[%s]", "fix typo", "it's"})
+
+	result := ctrl.execOne(context.Background(), repo, command, &out)
+	is.NoErr(result.err)
+	is.Equal(result.exitCode, 0)
+	is.Equal(out.String(), "[fix typo][it's]")
+}
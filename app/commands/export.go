@@ -0,0 +1,228 @@
+package commands
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hay-kot/repomgr/app/console"
+	"github.com/hay-kot/repomgr/app/core/repostore"
+)
+
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+)
+
+// csvHeader is the column layout for CSV exports. Artifacts are not included
+// in CSV exports, use JSON for a complete export. An empty is_archived or
+// synced_at means the value is unknown.
+var csvHeader = []string{
+	"remote_id",
+	"owner",
+	"name",
+	"description",
+	"html_url",
+	"clone_url",
+	"clone_ssh_url",
+	"is_fork",
+	"fork_url",
+	"is_archived",
+	"synced_at",
+	"pinned",
+	"tags",
+	"note",
+}
+
+// Export writes every repository in the database to the writer in the given
+// format.
+func (ctrl *Controller) Export(ctx context.Context, w io.Writer, format string) error {
+	records, err := ctrl.store.Export(ctx)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		err := cw.Write(csvHeader)
+		if err != nil {
+			return err
+		}
+
+		for _, r := range records {
+			archived := ""
+			if r.IsArchived != nil {
+				archived = strconv.FormatBool(*r.IsArchived)
+			}
+
+			syncedAt := ""
+			if !r.SyncedAt.IsZero() {
+				syncedAt = r.SyncedAt.Format(time.RFC3339)
+			}
+
+			err := cw.Write([]string{
+				r.RemoteID,
+				r.Owner,
+				r.Name,
+				r.Description,
+				r.HTMLURL,
+				r.CloneURL,
+				r.CloneSSHURL,
+				strconv.FormatBool(r.IsFork),
+				r.ForkURL,
+				archived,
+				syncedAt,
+				strconv.FormatBool(r.Pinned),
+				strings.Join(r.Tags, ";"),
+				r.Note,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		cw.Flush()
+		return cw.Error()
+	default:
+		return fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+// Import reads the repositories from the reader in the given format and merges
+// them into the database.
+func (ctrl *Controller) Import(ctx context.Context, r io.Reader, format string) error {
+	var (
+		records []repostore.Record
+		err     error
+	)
+
+	switch format {
+	case FormatJSON:
+		err = json.NewDecoder(r).Decode(&records)
+	case FormatCSV:
+		records, err = decodeCSV(r)
+	default:
+		return fmt.Errorf("unsupported import format: %s", format)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = ctrl.store.Import(ctx, records)
+	if err != nil {
+		return err
+	}
+
+	ctrl.cons.List("Import", []console.ListItem{
+		{StatusOk: true, Status: fmt.Sprintf("merged %d repositories", len(records))},
+	})
+	return nil
+}
+
+func decodeCSV(r io.Reader) ([]repostore.Record, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	// map columns by header name so column order is not significant
+	cols := make(map[string]int, len(rows[0]))
+	for i, name := range rows[0] {
+		cols[name] = i
+	}
+
+	for _, name := range []string{"remote_id", "owner", "name"} {
+		if _, ok := cols[name]; !ok {
+			return nil, fmt.Errorf("missing required column '%s'", name)
+		}
+	}
+
+	records := make([]repostore.Record, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		get := func(name string) string {
+			idx, ok := cols[name]
+			if !ok || idx >= len(row) {
+				return ""
+			}
+			return row[idx]
+		}
+
+		getBool := func(name string) (bool, error) {
+			v := get(name)
+			if v == "" {
+				return false, nil
+			}
+
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return false, fmt.Errorf("invalid value for '%s' on row %d: %w", name, i+2, err)
+			}
+			return b, nil
+		}
+
+		isFork, err := getBool("is_fork")
+		if err != nil {
+			return nil, err
+		}
+
+		pinned, err := getBool("pinned")
+		if err != nil {
+			return nil, err
+		}
+
+		var archived *bool
+		if get("is_archived") != "" {
+			v, err := getBool("is_archived")
+			if err != nil {
+				return nil, err
+			}
+			archived = &v
+		}
+
+		var syncedAt time.Time
+		if v := get("synced_at"); v != "" {
+			syncedAt, err = time.Parse(time.RFC3339, v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for 'synced_at' on row %d: %w", i+2, err)
+			}
+		}
+
+		var tags []string
+		if v := get("tags"); v != "" {
+			tags = strings.Split(v, ";")
+		}
+
+		records = append(records, repostore.Record{
+			RemoteID:    get("remote_id"),
+			Owner:       get("owner"),
+			Name:        get("name"),
+			Description: get("description"),
+			HTMLURL:     get("html_url"),
+			CloneURL:    get("clone_url"),
+			CloneSSHURL: get("clone_ssh_url"),
+			IsFork:      isFork,
+			ForkURL:     get("fork_url"),
+			IsArchived:  archived,
+			SyncedAt:    syncedAt,
+			Pinned:      pinned,
+			Tags:        tags,
+			Note:        get("note"),
+		})
+	}
+
+	return records, nil
+}
@@ -0,0 +1,171 @@
+package commands
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"github.com/hay-kot/repomgr/app/repos"
+)
+
+// RepoFilter narrows the repositories in the store for the non-interactive
+// commands. Zero values do not filter.
+type RepoFilter struct {
+	// Match is a list of glob patterns matched against "owner/name", a
+	// repository is included if any pattern matches.
+	Match []string
+	// Owners is a list of owners, a repository is included if it belongs to
+	// any of the owners.
+	Owners []string
+	// Fork includes only forks when true, and excludes forks when false.
+	Fork *bool
+	// Cloned includes only cloned repositories when true, and only repositories
+	// that are not cloned when false.
+	Cloned *bool
+	// Languages is a list of languages, a repository is included if it uses any
+	// of the languages. Requires the languages artifact to be cached.
+	Languages []string
+	// Tags is a list of tags, a repository is included if it has all the tags.
+	Tags []string
+}
+
+// IsZero returns true if the filter does not exclude any repositories.
+func (f RepoFilter) IsZero() bool {
+	return len(f.Match) == 0 &&
+		len(f.Owners) == 0 &&
+		f.Fork == nil &&
+		f.Cloned == nil &&
+		len(f.Languages) == 0 &&
+		len(f.Tags) == 0
+}
+
+// filterRepos returns the repositories in the store that match the filter.
+func (ctrl *Controller) filterRepos(ctx context.Context, f RepoFilter) ([]repos.Repository, error) {
+	all, err := ctrl.store.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]repos.Repository, 0, len(all))
+	for _, repo := range all {
+		ok, err := ctrl.matchesFilter(ctx, f, repo)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			results = append(results, repo)
+		}
+	}
+
+	return results, nil
+}
+
+func (ctrl *Controller) matchesFilter(ctx context.Context, f RepoFilter, repo repos.Repository) (bool, error) {
+	if len(f.Match) > 0 && !matchesAny(f.Match, repo.DisplayName()) {
+		return false, nil
+	}
+
+	if len(f.Owners) > 0 && !containsFold(f.Owners, repo.Owner) {
+		return false, nil
+	}
+
+	if f.Fork != nil && *f.Fork != repo.IsFork {
+		return false, nil
+	}
+
+	for _, tag := range f.Tags {
+		if !repo.HasTag(strings.ToLower(tag)) {
+			return false, nil
+		}
+	}
+
+	if f.Cloned != nil && *f.Cloned != ctrl.rfs.IsCloned(repo) {
+		return false, nil
+	}
+
+	if len(f.Languages) > 0 {
+		langs, err := ctrl.store.GetLanguages(ctx, repo.ID)
+		if err != nil {
+			return false, err
+		}
+
+		found := false
+		for _, lang := range langs {
+			if containsFold(f.Languages, lang) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// matchesAny returns true if the str matches any of the glob patterns.
+func matchesAny(patterns []string, str string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, str); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsFold(list []string, str string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, str) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resolveRepos returns the repositories for the "owner/name" arguments combined
+// with the repositories matching the filter. Repositories are deduplicated and
+// returned in the order they were resolved. The filter is only applied when names
+// is empty or the filter is set, so arguments alone select exactly the named
+// repositories.
+func (ctrl *Controller) resolveRepos(ctx context.Context, names []string, f RepoFilter) ([]repos.Repository, error) {
+	var (
+		results = make([]repos.Repository, 0, len(names))
+		seen    = make(map[int]bool, len(names))
+	)
+
+	add := func(repo repos.Repository) {
+		if seen[repo.ID] {
+			return
+		}
+
+		seen[repo.ID] = true
+		results = append(results, repo)
+	}
+
+	for _, name := range names {
+		repo, err := ctrl.store.GetByDisplayName(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+
+		add(repo)
+	}
+
+	if len(names) == 0 || !f.IsZero() {
+		matched, err := ctrl.filterRepos(ctx, f)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, repo := range matched {
+			add(repo)
+		}
+	}
+
+	return results, nil
+}
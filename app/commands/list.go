@@ -0,0 +1,148 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hay-kot/repomgr/app/console"
+	"github.com/hay-kot/repomgr/app/repos"
+	"github.com/hay-kot/repomgr/internal/quicktmpl"
+)
+
+const (
+	FormatTable     = "table"
+	FormatJSONLines = "jsonl"
+)
+
+// listItem is the serialized representation of a repository for the list command.
+type listItem struct {
+	Owner       string   `json:"owner"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	HTMLURL     string   `json:"html_url"`
+	CloneURL    string   `json:"clone_url"`
+	CloneSSHURL string   `json:"clone_ssh_url"`
+	IsFork      bool     `json:"is_fork"`
+	IsArchived  bool     `json:"is_archived"`
+	Pinned      bool     `json:"pinned"`
+	Tags        []string `json:"tags"`
+	Note        string   `json:"note"`
+	CloneDir    string   `json:"clone_dir"`
+	Cloned      bool     `json:"cloned"`
+}
+
+// List writes the repositories matching the filter to the writer. The format is
+// one of "table", "json" or "jsonl", or a template containing "{{" that is
+// rendered for each repository with the same data as key bindings (.Repo,
+// .CloneDir) plus .Cloned.
+func (ctrl *Controller) List(ctx context.Context, w io.Writer, format string, f RepoFilter) error {
+	items, err := ctrl.filterRepos(ctx, f)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "", FormatTable:
+		rows := make([][]string, len(items))
+		for i, repo := range items {
+			cloned := ""
+			if ctrl.rfs.IsCloned(repo) {
+				cloned = "yes"
+			}
+
+			rows[i] = []string{repo.DisplayName(), cloned, strings.Join(repo.Tags, ", "), repo.Description}
+		}
+
+		return console.WriteTable(w, []string{"repository", "cloned", "tags", "description"}, rows)
+	case FormatJSON:
+		out := make([]listItem, len(items))
+		for i, repo := range items {
+			out[i], err = ctrl.toListItem(repo)
+			if err != nil {
+				return err
+			}
+		}
+
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	case FormatJSONLines:
+		enc := json.NewEncoder(w)
+		for _, repo := range items {
+			item, err := ctrl.toListItem(repo)
+			if err != nil {
+				return err
+			}
+
+			err = enc.Encode(item)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	default:
+		if !strings.Contains(format, "{{") {
+			return fmt.Errorf("unsupported format: %s", format)
+		}
+
+		tmpl, err := quicktmpl.New(format)
+		if err != nil {
+			return fmt.Errorf("invalid format template: %w", err)
+		}
+
+		for _, repo := range items {
+			cloneDir, err := ctrl.rfs.FindCloneDirectory(repo)
+			if err != nil {
+				return err
+			}
+
+			err = tmpl.Execute(w, quicktmpl.Data{
+				"Repo":     repo,
+				"CloneDir": cloneDir,
+				"Cloned":   ctrl.rfs.IsCloned(repo),
+			})
+			if err != nil {
+				return err
+			}
+
+			_, err = io.WriteString(w, "\n")
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+func (ctrl *Controller) toListItem(repo repos.Repository) (listItem, error) {
+	cloneDir, err := ctrl.rfs.FindCloneDirectory(repo)
+	if err != nil {
+		return listItem{}, err
+	}
+
+	tags := repo.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return listItem{
+		Owner:       repo.Owner,
+		Name:        repo.Name,
+		Description: repo.Description,
+		HTMLURL:     repo.HTMLURL,
+		CloneURL:    repo.CloneURL,
+		CloneSSHURL: repo.CloneSSHURL,
+		IsFork:      repo.IsFork,
+		IsArchived:  repo.IsArchived,
+		Pinned:      repo.Pinned,
+		Tags:        tags,
+		Note:        repo.Note,
+		CloneDir:    cloneDir,
+		Cloned:      ctrl.rfs.IsCloned(repo),
+	}, nil
+}
@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/hay-kot/repomgr/app/core/config"
+	"github.com/hay-kot/repomgr/app/core/repofs"
+	"github.com/hay-kot/repomgr/app/repos"
+	"github.com/matryer/is"
+)
+
+func Test_Controller_List_Format(t *testing.T) {
+	type tcase struct {
+		name    string
+		format  string
+		want    string
+		wantErr bool
+	}
+
+	cases := []tcase{
+		{name: "table", format: FormatTable, want: " REPOSITORY       CLONED  TAGS  DESCRIPTION\n hay-kot/repomgr                \n"},
+		{name: "template", format: "{{ .Repo.DisplayName }}", want: "hay-kot/repomgr\n"},
+		{name: "jsonl", format: FormatJSONLines},
+		{name: "unsupported", format: "yaml", wantErr: true},
+		{name: "misspelled", format: "jsonlines", wantErr: true},
+	}
+
+	store := tStore(t)
+
+	err := store.UpsertOne(context.Background(), repos.Repository{
+		RemoteID: "1",
+		Name:     "repomgr",
+		Owner:    "hay-kot",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctrl := &Controller{
+		conf:  &config.Config{},
+		store: store,
+		rfs: repofs.New(repofs.CloneDirectories{
+			Default: filepath.Join(t.TempDir(), "{{ .Repo.Owner }}", "{{ .Repo.Name }}"),
+		}),
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			var buf bytes.Buffer
+			err := ctrl.List(context.Background(), &buf, tc.format, RepoFilter{})
+			if tc.wantErr {
+				is.True(err != nil)
+				is.Equal(buf.Len(), 0)
+				return
+			}
+
+			is.NoErr(err)
+			if tc.want != "" {
+				is.Equal(buf.String(), tc.want)
+			}
+		})
+	}
+}
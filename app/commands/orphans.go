@@ -0,0 +1,155 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+
+	"github.com/hay-kot/repomgr/app/console"
+	"github.com/hay-kot/repomgr/app/core/git"
+	"github.com/hay-kot/repomgr/app/core/repofs"
+	"github.com/hay-kot/repomgr/app/repos"
+)
+
+// OrphanKind describes why a clone found in the clone roots is reported.
+type OrphanKind string
+
+const (
+	// OrphanKindOrphan is a clone that does not belong to any cached repository.
+	OrphanKindOrphan OrphanKind = "orphan"
+	// OrphanKindMisplaced is a clone of a cached repository in a different
+	// directory than the clone directory matchers compute.
+	OrphanKindMisplaced OrphanKind = "misplaced"
+	// OrphanKindDuplicate is a misplaced clone of a repository that is also
+	// cloned in the expected directory.
+	OrphanKindDuplicate OrphanKind = "duplicate"
+)
+
+// orphanItem is the serialized representation of a clone for the orphans
+// command.
+type orphanItem struct {
+	Kind       OrphanKind `json:"kind"`
+	Path       string     `json:"path"`
+	Remote     string     `json:"remote"`
+	Repository string     `json:"repository,omitempty"`
+	Expected   string     `json:"expected,omitempty"`
+}
+
+// Orphans scans the clone roots, up to depth directories deep, for git
+// repositories that do not belong to any cached repository or that are not in
+// the directory computed by the clone directory matchers, and writes them to
+// the writer. The format is one of "table" or "json".
+func (ctrl *Controller) Orphans(ctx context.Context, w io.Writer, format string, depth int) error {
+	roots := ctrl.conf.CloneDirectories.Roots()
+
+	items, err := ctrl.findOrphans(ctx, roots, depth)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "", FormatTable:
+		if len(items) == 0 {
+			ctrl.cons.List("Orphans", []console.ListItem{
+				{StatusOk: true, Status: fmt.Sprintf("no orphaned or misplaced clones found in %d clone roots", len(roots))},
+			})
+			return nil
+		}
+
+		rows := make([][]string, len(items))
+		for i, item := range items {
+			rows[i] = []string{string(item.Kind), item.Path, item.Repository, item.Expected}
+		}
+
+		return console.WriteTable(w, []string{"kind", "path", "repository", "expected"}, rows)
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(items)
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// findOrphans returns the orphaned, misplaced and duplicate clones in the
+// roots, sorted by path.
+func (ctrl *Controller) findOrphans(ctx context.Context, roots []string, depth int) ([]orphanItem, error) {
+	all, err := ctrl.store.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		expected = make(map[string]repos.Repository, len(all))
+		byRemote = make(map[string]repos.Repository, len(all))
+	)
+
+	for _, repo := range all {
+		dir, err := ctrl.rfs.FindCloneDirectory(repo)
+		if err != nil {
+			return nil, err
+		}
+		expected[filepath.Clean(dir)] = repo
+
+		for _, remote := range []string{repo.CloneURL, repo.CloneSSHURL, repo.HTMLURL} {
+			host, path, ok := git.ParseRemote(remote)
+			if ok {
+				byRemote[host+"/"+path] = repo
+			}
+		}
+	}
+
+	var found []string
+	for _, root := range roots {
+		paths, err := repofs.FindGitRepositories(root, depth)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, path := range paths {
+			// roots may be nested, so the same clone can be found twice
+			if !slices.Contains(found, path) {
+				found = append(found, path)
+			}
+		}
+	}
+	slices.Sort(found)
+
+	items := []orphanItem{}
+	for _, path := range found {
+		if _, ok := expected[path]; ok {
+			continue
+		}
+
+		item := orphanItem{Kind: OrphanKindOrphan, Path: path}
+
+		// clones without an origin remote are orphans
+		remote, err := git.RemoteURL(ctx, path, "origin")
+		if err == nil {
+			item.Remote = remote
+		}
+
+		host, remotePath, ok := git.ParseRemote(remote)
+		if repo, match := byRemote[host+"/"+remotePath]; ok && match {
+			dir, err := ctrl.rfs.FindCloneDirectory(repo)
+			if err != nil {
+				return nil, err
+			}
+
+			item.Kind = OrphanKindMisplaced
+			item.Repository = repo.DisplayName()
+			item.Expected = dir
+
+			if ctrl.rfs.IsCloned(repo) {
+				item.Kind = OrphanKindDuplicate
+			}
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,131 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hay-kot/repomgr/app/console"
+	"github.com/hay-kot/repomgr/app/core/git"
+	"github.com/hay-kot/repomgr/app/repos"
+	"github.com/sourcegraph/conc/pool"
+)
+
+type pullResult struct {
+	repo    repos.Repository
+	status  string
+	skipped bool
+	err     error
+}
+
+// Pull fetches and fast-forwards the cloned repositories for the "owner/name"
+// arguments and the repositories matching the filter, or all cloned
+// repositories if neither are set. Repositories with uncommitted or untracked
+// changes are skipped, and branches that cannot be fast-forwarded are reported
+// as failures.
+func (ctrl *Controller) Pull(ctx context.Context, names []string, f RepoFilter) error {
+	items, err := ctrl.resolveRepos(ctx, names, f)
+	if err != nil {
+		return err
+	}
+
+	cloned := make([]repos.Repository, 0, len(items))
+	for _, repo := range items {
+		if ctrl.rfs.IsCloned(repo) {
+			cloned = append(cloned, repo)
+		}
+	}
+
+	results := make([]pullResult, len(cloned))
+
+	p := pool.New().WithMaxGoroutines(ctrl.conf.Concurrency)
+	for i, repo := range cloned {
+		p.Go(func() {
+			results[i] = ctrl.pullOne(ctx, repo)
+		})
+	}
+	p.Wait()
+
+	var (
+		pulled  = make([]console.ListItem, 0, len(results))
+		failed  = make([]console.ListItem, 0)
+		skipped = 0
+	)
+
+	for _, r := range results {
+		if r.err != nil {
+			failed = append(failed, console.ListItem{
+				StatusOk: false,
+				Status:   fmt.Sprintf("%s: %s", r.repo.DisplayName(), r.err),
+			})
+			continue
+		}
+
+		if r.skipped {
+			skipped++
+		}
+
+		pulled = append(pulled, console.ListItem{
+			StatusOk: true,
+			Status:   fmt.Sprintf("%s %s", r.repo.DisplayName(), r.status),
+		})
+	}
+
+	title := fmt.Sprintf("Pull (%d pulled, %d skipped, %d failed)", len(pulled)-skipped, skipped, len(failed))
+	ctrl.cons.List(title, pulled)
+
+	if len(failed) > 0 {
+		ctrl.cons.LineBreak()
+		ctrl.cons.List("Failed", failed)
+		return fmt.Errorf("failed to pull %d repositories", len(failed))
+	}
+
+	return nil
+}
+
+func (ctrl *Controller) pullOne(ctx context.Context, repo repos.Repository) pullResult {
+	result := pullResult{repo: repo}
+
+	dir, err := ctrl.rfs.FindCloneDirectory(repo)
+	if err != nil {
+		result.err = err
+		return result
+	}
+
+	status, err := git.GetStatus(ctx, dir)
+	if err != nil {
+		result.err = err
+		return result
+	}
+
+	if status.IsDirty() {
+		result.skipped = true
+		result.status = "skipped, working tree has changes"
+		return result
+	}
+
+	err = git.Fetch(ctx, dir)
+	if err != nil {
+		result.err = err
+		return result
+	}
+
+	if status.Upstream == "" {
+		result.skipped = true
+		result.status = fmt.Sprintf("fetched, %s has no upstream to pull", status.Branch)
+		return result
+	}
+
+	updated, err := git.FastForward(ctx, dir)
+	if err != nil {
+		result.err = err
+		return result
+	}
+
+	if updated {
+		result.status = "updated"
+	} else {
+		result.status = "already up to date"
+	}
+
+	return result
+}
@@ -0,0 +1,210 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hay-kot/repomgr/app/console"
+	"github.com/hay-kot/repomgr/app/core/repostore"
+)
+
+// relocation is a planned move of a clone, conflict is set if the clone
+// cannot be moved.
+type relocation struct {
+	repo     string
+	from     string
+	to       string
+	conflict string
+}
+
+// Relocate moves the clones found in the clone roots that are not in the
+// directory computed by the clone directory matchers to that directory.
+// Moves that would overwrite an existing directory or collide with another
+// move are reported as conflicts and skipped. Every move is journaled before
+// it is made so the run can be reverted with RelocateUndo.
+func (ctrl *Controller) Relocate(ctx context.Context, depth int, opts MaintenanceOptions) error {
+	items, err := ctrl.findOrphans(ctx, ctrl.conf.CloneDirectories.Roots(), depth)
+	if err != nil {
+		return err
+	}
+
+	plan := make([]relocation, 0, len(items))
+	for _, item := range items {
+		if item.Kind == OrphanKindOrphan {
+			continue
+		}
+
+		plan = append(plan, relocation{repo: item.Repository, from: item.Path, to: item.Expected})
+	}
+
+	detectConflicts(plan)
+
+	var (
+		rows  = make([][]string, len(plan))
+		moves = make([]repostore.Relocation, 0, len(plan))
+	)
+
+	for i, r := range plan {
+		status := "move"
+		if r.conflict != "" {
+			status = "conflict: " + r.conflict
+		} else {
+			moves = append(moves, repostore.Relocation{From: r.from, To: r.to})
+		}
+
+		rows[i] = []string{r.repo, r.from, r.to, status}
+	}
+
+	if len(moves) == 0 {
+		if len(rows) > 0 {
+			ctrl.cons.Table([]string{"repository", "from", "to", "status"}, rows)
+			ctrl.cons.LineBreak()
+		}
+
+		ctrl.cons.List("Relocate", []console.ListItem{
+			{StatusOk: true, Status: "no clones to move"},
+		})
+		return nil
+	}
+
+	ctrl.cons.Table([]string{"repository", "from", "to", "status"}, rows)
+
+	if !ctrl.confirm(opts, fmt.Sprintf("Move %d clones?", len(moves))) {
+		return nil
+	}
+
+	journal, err := ctrl.store.JournalRelocations(ctx, moves)
+	if err != nil {
+		return err
+	}
+
+	return ctrl.applyRelocations("Relocate", journal, false)
+}
+
+// RelocateUndo moves the clones of the most recent relocate run that has not
+// been undone back to their previous directories.
+func (ctrl *Controller) RelocateUndo(ctx context.Context, opts MaintenanceOptions) error {
+	journal, err := ctrl.store.UndoableRelocations(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(journal) == 0 {
+		ctrl.cons.List("Relocate Undo", []console.ListItem{
+			{StatusOk: true, Status: "no relocations to undo"},
+		})
+		return nil
+	}
+
+	rows := make([][]string, len(journal))
+	for i, r := range journal {
+		rows[i] = []string{r.To, r.From, r.MovedAt.Format("2006-01-02 15:04")}
+	}
+
+	ctrl.cons.Table([]string{"from", "to", "moved at"}, rows)
+
+	if !ctrl.confirm(opts, fmt.Sprintf("Move %d clones back?", len(journal))) {
+		return nil
+	}
+
+	return ctrl.applyRelocations("Relocate Undo", journal, true)
+}
+
+// applyRelocations moves the clones of the journal entries, or moves them back
+// if undo is set, marking every entry in the journal once it has been moved.
+func (ctrl *Controller) applyRelocations(title string, journal []repostore.Relocation, undo bool) error {
+	var (
+		items  = make([]console.ListItem, len(journal))
+		failed = 0
+	)
+
+	for i, r := range journal {
+		from, to := r.From, r.To
+		if undo {
+			from, to = r.To, r.From
+		}
+
+		err := moveClone(from, to)
+		if err == nil {
+			// the clone has been moved, the journal must reflect it even if
+			// the command is interrupted
+			if undo {
+				err = ctrl.store.MarkRelocationUndone(context.Background(), r.ID)
+			} else {
+				err = ctrl.store.MarkRelocated(context.Background(), r.ID)
+			}
+		}
+
+		if err != nil {
+			failed++
+			items[i] = console.ListItem{StatusOk: false, Status: fmt.Sprintf("%s: %s", from, err)}
+			continue
+		}
+
+		items[i] = console.ListItem{StatusOk: true, Status: fmt.Sprintf("%s moved to %s", from, to)}
+	}
+
+	ctrl.cons.LineBreak()
+	ctrl.cons.List(title, items)
+
+	if failed > 0 {
+		return fmt.Errorf("failed to move %d clones", failed)
+	}
+
+	return nil
+}
+
+// detectConflicts sets the conflict of the relocations that would overwrite an
+// existing directory, share a destination or move a clone into itself.
+func detectConflicts(plan []relocation) {
+	destinations := make(map[string]int, len(plan))
+	for _, r := range plan {
+		destinations[r.to]++
+	}
+
+	for i, r := range plan {
+		switch {
+		case destinations[r.to] > 1:
+			plan[i].conflict = "multiple clones for the same destination"
+		case isWithin(r.to, r.from), isWithin(r.from, r.to):
+			plan[i].conflict = "destination overlaps the clone"
+		default:
+			_, err := os.Stat(r.to)
+			if err == nil {
+				plan[i].conflict = "destination already exists"
+			} else if !errors.Is(err, os.ErrNotExist) {
+				plan[i].conflict = err.Error()
+			}
+		}
+	}
+}
+
+// isWithin returns true if path is inside of dir.
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+
+	return rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// moveClone moves the clone at from to to, creating the parent directories of
+// to as required. The destination must not exist.
+func moveClone(from, to string) error {
+	_, err := os.Stat(to)
+	if err == nil {
+		return fmt.Errorf("destination %s already exists", to)
+	}
+
+	err = os.MkdirAll(filepath.Dir(to), 0o755)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(from, to)
+}
@@ -0,0 +1,163 @@
+package commands
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hay-kot/repomgr/app/console"
+	"github.com/hay-kot/repomgr/app/core/config"
+	"github.com/hay-kot/repomgr/app/core/git"
+	"github.com/hay-kot/repomgr/app/core/repofs"
+	"github.com/hay-kot/repomgr/app/repos"
+	"github.com/matryer/is"
+)
+
+func Test_isWithin(t *testing.T) {
+	type tcase struct {
+		path string
+		dir  string
+		want bool
+	}
+
+	cases := []tcase{
+		{path: "/repos/hay-kot/repomgr", dir: "/repos/hay-kot", want: true},
+		{path: "/repos/hay-kot/repomgr/nested", dir: "/repos/hay-kot", want: true},
+		{path: "/repos/hay-kot", dir: "/repos/hay-kot", want: false},
+		{path: "/repos/hay-kot-old", dir: "/repos/hay-kot", want: false},
+		{path: "/repos", dir: "/repos/hay-kot", want: false},
+		{path: "/repos/hay-kot/../acme", dir: "/repos/hay-kot", want: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.path+" in "+tc.dir, func(t *testing.T) {
+			is := is.New(t)
+			is.Equal(isWithin(tc.path, tc.dir), tc.want)
+		})
+	}
+}
+
+func Test_detectConflicts(t *testing.T) {
+	root := t.TempDir()
+	path := func(elem ...string) string {
+		return filepath.Join(append([]string{root}, elem...)...)
+	}
+
+	if err := os.MkdirAll(path("exists"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	type tcase struct {
+		name string
+		plan []relocation
+		want []string
+	}
+
+	cases := []tcase{
+		{
+			name: "no conflict",
+			plan: []relocation{{from: path("old"), to: path("new")}},
+			want: []string{""},
+		},
+		{
+			name: "shared destination",
+			plan: []relocation{
+				{from: path("a"), to: path("new")},
+				{from: path("b"), to: path("new")},
+				{from: path("c"), to: path("other")},
+			},
+			want: []string{
+				"multiple clones for the same destination",
+				"multiple clones for the same destination",
+				"",
+			},
+		},
+		{
+			name: "destination nested in the source",
+			plan: []relocation{{from: path("repomgr"), to: path("repomgr", "hay-kot", "repomgr")}},
+			want: []string{"destination overlaps the clone"},
+		},
+		{
+			name: "source nested in the destination",
+			plan: []relocation{{from: path("hay-kot", "repomgr"), to: path("hay-kot")}},
+			want: []string{"destination overlaps the clone"},
+		},
+		{
+			name: "existing destination",
+			plan: []relocation{{from: path("old"), to: path("exists")}},
+			want: []string{"destination already exists"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			detectConflicts(tc.plan)
+
+			got := make([]string, len(tc.plan))
+			for i, r := range tc.plan {
+				got[i] = r.conflict
+			}
+
+			is.Equal(got, tc.want)
+		})
+	}
+}
+
+func Test_Controller_Relocate_Undo(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	root := t.TempDir()
+	conf := &config.Config{
+		CloneDirectories: repofs.CloneDirectories{
+			Default: filepath.Join(root, "{{ .Repo.Owner }}", "{{ .Repo.Name }}"),
+		},
+	}
+
+	ctrl := &Controller{
+		conf:  conf,
+		store: tStore(t),
+		rfs:   repofs.New(conf.CloneDirectories),
+		cons:  console.NewConsole(io.Discard, false),
+	}
+
+	is.NoErr(ctrl.store.UpsertOne(ctx, repos.Repository{
+		RemoteID: "1",
+		Owner:    "hay-kot",
+		Name:     "repomgr",
+		CloneURL: "https://github.com/hay-kot/repomgr.git",
+	}))
+
+	misplaced := filepath.Join(root, "old", "repomgr")
+	expected := filepath.Join(root, "hay-kot", "repomgr")
+
+	is.NoErr(os.MkdirAll(misplaced, 0o755))
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"remote", "add", "origin", "git@github.com:hay-kot/repomgr.git"},
+	} {
+		_, err := git.Run(ctx, misplaced, args...)
+		is.NoErr(err)
+	}
+
+	exists := func(path string) bool {
+		_, err := os.Stat(filepath.Join(path, ".git"))
+		return err == nil
+	}
+
+	is.NoErr(ctrl.Relocate(ctx, 3, MaintenanceOptions{Yes: true}))
+	is.True(exists(expected))   // the clone is moved to the expected directory
+	is.True(!exists(misplaced)) // and removed from the old one
+
+	is.NoErr(ctrl.RelocateUndo(ctx, MaintenanceOptions{Yes: true}))
+	is.True(exists(misplaced)) // the clone is moved back
+	is.True(!exists(expected))
+
+	// the undone run cannot be undone again
+	is.NoErr(ctrl.RelocateUndo(ctx, MaintenanceOptions{Yes: true}))
+	is.True(exists(misplaced))
+}
@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/hay-kot/repomgr/app/core/commander"
+)
+
+// Run executes the command for the key binding against the repository matching
+// the "owner/name" string in the foreground, following the same mode semantics
+// as the search UI:
+//
+//   - readonly actions write their output to w
+//   - interactive actions are attached to the terminal
+//   - background actions discard their output and only report errors
+//
+// Exit actions write their message to w and pin actions toggle the pinned
+// state of the repository.
+func (ctrl *Controller) Run(ctx context.Context, w io.Writer, key string, name string) error {
+	binding, ok := ctrl.conf.KeyBindings[key]
+	if !ok {
+		keys := make([]string, 0, len(ctrl.conf.KeyBindings))
+		for k := range ctrl.conf.KeyBindings {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+
+		return fmt.Errorf("no key binding found for '%s', available bindings: %v", key, keys)
+	}
+
+	repo, err := ctrl.store.GetByDisplayName(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	action, ok := ctrl.commander.GetAction(key, repo)
+	if !ok {
+		return fmt.Errorf("failed to render command for key binding '%s': %s", key, binding.Cmd)
+	}
+
+	if action.IsExit() {
+		_, err = fmt.Fprintln(w, action.ExitMessage())
+		return err
+	}
+
+	if action.IsPin() {
+		pinned, err := ctrl.store.TogglePin(ctx, repo.ID)
+		if err != nil {
+			return err
+		}
+
+		state := "unpinned"
+		if pinned {
+			state = "pinned"
+		}
+
+		_, err = fmt.Fprintf(w, "%s %s\n", repo.DisplayName(), state)
+		return err
+	}
+
+	switch action.Mode {
+	case commander.ModeReadOnly:
+		action.SetWriter(w)
+	case commander.ModeInteractive:
+		cmd, ok := action.IsExec()
+		if !ok {
+			return fmt.Errorf("key binding '%s' is interactive but is not an exec command", key)
+		}
+
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+	case commander.ModeBackground:
+		action.SetWriter(io.Discard)
+	default:
+		return fmt.Errorf("unsupported mode '%s' for key binding '%s'", action.Mode, key)
+	}
+
+	return action.Run()
+}
@@ -2,9 +2,15 @@ package commands
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/hay-kot/repomgr/app/commands/ui"
+	"github.com/hay-kot/repomgr/app/core/daemon"
+	"github.com/rs/zerolog/log"
 )
 
 func (ctrl *Controller) Search(ctx context.Context) (string, error) {
@@ -14,12 +20,37 @@ func (ctrl *Controller) Search(ctx context.Context) (string, error) {
 	}
 
 	var (
-		searchCtrl = ui.NewSearchCtrl(r, ctrl.rfs, ctrl.commander)
+		searchCtrl = ui.NewSearchCtrl(r, ctrl.rfs, ctrl.commander, ctrl.store)
 		search     = ui.NewSearchView(searchCtrl)
 		layout     = ui.NewLayout(search)
 	)
 
+	status, err := daemon.ReadStatus(ctrl.conf.Daemon.StatusFile)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to read daemon status")
+	}
+	searchCtrl.SetLastSync(status.LastSync)
+
+	if ctrl.conf.Artifacts.Background && len(ctrl.conf.Artifacts.Types) > 0 {
+		refreshctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		go func() {
+			_ = ctrl.refresher().Run(refreshctx, ctrl.conf.Artifacts.Interval)
+		}()
+	}
+
 	p := tea.NewProgram(layout, tea.WithAltScreen())
+
+	if ctrl.shouldRefresh(status) {
+		searchCtrl.SetSyncing(true)
+
+		syncctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		go ctrl.backgroundSync(syncctx, p.Send)
+	}
+
 	_, err = p.Run()
 	if err != nil {
 		return "", err
@@ -28,3 +59,109 @@ func (ctrl *Controller) Search(ctx context.Context) (string, error) {
 	msg := searchCtrl.ExitMessage()
 	return msg, nil
 }
+
+// shouldRefresh returns true if the cache is older than the configured ttl and
+// no daemon is running that syncs it on its own schedule.
+func (ctrl *Controller) shouldRefresh(status daemon.Status) bool {
+	ttl := ctrl.conf.Cache.TTL
+	if ttl <= 0 || !status.IsStale(ttl, time.Now()) {
+		return false
+	}
+
+	pid, err := daemon.LockHolder(ctrl.conf.Daemon.LockFile)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to read daemon lock")
+		return false
+	}
+
+	return pid == 0
+}
+
+// backgroundSync syncs the cache while the search UI is open, sending the
+// updated repositories to the UI as soon as they are saved.
+func (ctrl *Controller) backgroundSync(ctx context.Context, send func(tea.Msg)) {
+	start := time.Now()
+
+	result, err := ctrl.sync(ctx, logSyncEvent, func() {
+		r, err := ctrl.store.GetAll(ctx)
+		if err != nil {
+			send(ui.SyncMsg{Err: err})
+			return
+		}
+
+		send(ui.SyncMsg{Repos: r, LastSync: start})
+	})
+	if errors.Is(err, context.Canceled) {
+		// the UI was closed before the sync finished
+		return
+	}
+
+	ctrl.updateStatus(func(s *daemon.Status) {
+		recordSync(s, start, result, err)
+	})
+
+	if err != nil {
+		log.Warn().Err(err).Msg("background sync failed")
+		send(ui.SyncMsg{Err: err})
+		return
+	}
+
+	for _, s := range result.failed() {
+		log.Warn().Err(s.err).Str("source", s.source).Msg("failed to sync source")
+	}
+}
+
+const (
+	PrintName     = "name"
+	PrintCloneDir = "clone-dir"
+	PrintURL      = "url"
+	PrintCloneURL = "clone-url"
+	PrintSSHURL   = "ssh-url"
+)
+
+// SearchQuery runs the search without starting the UI and writes up to limit
+// matches to the writer, one per line, formatted according to print. A limit
+// less than 1 writes all matches. An error is returned if nothing matches.
+func (ctrl *Controller) SearchQuery(ctx context.Context, w io.Writer, query string, limit int, print string) error {
+	r, err := ctrl.store.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	matches := ui.NewSearchCtrl(r, ctrl.rfs, ctrl.commander, ctrl.store).Search(query)
+	if len(matches) == 0 {
+		return fmt.Errorf("no repositories matched '%s'", query)
+	}
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	for _, repo := range matches {
+		var line string
+		switch print {
+		case "", PrintName:
+			line = repo.DisplayName()
+		case PrintCloneDir:
+			line, err = ctrl.rfs.FindCloneDirectory(repo)
+			if err != nil {
+				return err
+			}
+		case PrintURL:
+			line = repo.HTMLURL
+		case PrintCloneURL:
+			line = repo.CloneURL
+		case PrintSSHURL:
+			line = repo.CloneSSHURL
+		default:
+			return fmt.Errorf("unsupported print value: %s", print)
+		}
+
+		_, err = fmt.Fprintln(w, line)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/hay-kot/repomgr/app/core/commander"
+	"github.com/hay-kot/repomgr/app/core/config"
+	"github.com/hay-kot/repomgr/app/core/repofs"
+	"github.com/hay-kot/repomgr/app/repos"
+	"github.com/matryer/is"
+)
+
+func Test_Controller_SearchQuery_Print(t *testing.T) {
+	type tcase struct {
+		print string
+		want  string
+	}
+
+	cases := []tcase{
+		{print: PrintName, want: "hay-kot/repomgr\n"},
+		{print: PrintURL, want: "https://github.com/hay-kot/repomgr\n"},
+		{print: PrintCloneURL, want: "https://github.com/hay-kot/repomgr.git\n"},
+		{print: PrintSSHURL, want: "git@github.com:hay-kot/repomgr.git\n"},
+	}
+
+	store := tStore(t)
+	err := store.UpsertOne(context.Background(), repos.Repository{
+		RemoteID:    "1",
+		Name:        "repomgr",
+		Owner:       "hay-kot",
+		HTMLURL:     "https://github.com/hay-kot/repomgr",
+		CloneURL:    "https://github.com/hay-kot/repomgr.git",
+		CloneSSHURL: "git@github.com:hay-kot/repomgr.git",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf := config.Default()
+	rfs := repofs.New(repofs.CloneDirectories{
+		Default: filepath.Join(t.TempDir(), "{{ .Repo.Owner }}", "{{ .Repo.Name }}"),
+	})
+
+	ctrl := &Controller{
+		conf:      conf,
+		store:     store,
+		rfs:       rfs,
+		commander: commander.New(conf.KeyBindings, rfs, &commander.ShellCommandBuilder{}),
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.print, func(t *testing.T) {
+			is := is.New(t)
+
+			var buf bytes.Buffer
+			err := ctrl.SearchQuery(context.Background(), &buf, "repomgr", 1, tc.print)
+			is.NoErr(err)
+			is.Equal(buf.String(), tc.want)
+		})
+	}
+}
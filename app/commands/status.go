@@ -0,0 +1,165 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/hay-kot/repomgr/app/console"
+	"github.com/hay-kot/repomgr/app/core/git"
+	"github.com/hay-kot/repomgr/app/repos"
+	"github.com/sourcegraph/conc/pool"
+)
+
+// StatusOptions narrow the repositories reported by Status. Repositories that
+// fail to report a status are always included.
+type StatusOptions struct {
+	// Dirty only includes repositories with uncommitted or untracked changes.
+	Dirty bool
+	// Unpushed only includes repositories with work that only exists locally.
+	Unpushed bool
+}
+
+// statusItem is the serialized representation of a repository for the status
+// command.
+type statusItem struct {
+	Owner    string `json:"owner"`
+	Name     string `json:"name"`
+	CloneDir string `json:"clone_dir"`
+	git.Status
+	Error string `json:"error,omitempty"`
+}
+
+// Status inspects the working tree of every cloned repository matching the
+// filter concurrently and writes the branch, changes, stashes and
+// ahead/behind counts to the writer. The format is one of "table", "json" or
+// "jsonl". An error is returned if any repository could not be inspected.
+func (ctrl *Controller) Status(ctx context.Context, w io.Writer, format string, f RepoFilter, opts StatusOptions) error {
+	all, err := ctrl.filterRepos(ctx, f)
+	if err != nil {
+		return err
+	}
+
+	cloned := make([]repos.Repository, 0, len(all))
+	for _, repo := range all {
+		if ctrl.rfs.IsCloned(repo) {
+			cloned = append(cloned, repo)
+		}
+	}
+
+	results := make([]statusItem, len(cloned))
+
+	p := pool.New().WithMaxGoroutines(ctrl.conf.Concurrency)
+	for i, repo := range cloned {
+		p.Go(func() {
+			results[i] = ctrl.repoStatus(ctx, repo)
+		})
+	}
+	p.Wait()
+
+	var (
+		items  = make([]statusItem, 0, len(results))
+		failed = 0
+	)
+
+	for _, item := range results {
+		switch {
+		case item.Error != "":
+			failed++
+		case opts.Dirty && !item.IsDirty():
+			continue
+		case opts.Unpushed && !item.HasUnpushed():
+			continue
+		}
+
+		items = append(items, item)
+	}
+
+	switch format {
+	case "", FormatTable:
+		rows := make([][]string, len(items))
+		for i, item := range items {
+			rows[i] = statusRow(item)
+		}
+
+		err = console.WriteTable(w, []string{"repository", "branch", "dirty", "untracked", "stashes", "upstream"}, rows)
+		if err != nil {
+			return err
+		}
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		err = enc.Encode(items)
+		if err != nil {
+			return err
+		}
+	case FormatJSONLines:
+		enc := json.NewEncoder(w)
+		for _, item := range items {
+			err = enc.Encode(item)
+			if err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to get the status of %d repositories", failed)
+	}
+
+	return nil
+}
+
+func (ctrl *Controller) repoStatus(ctx context.Context, repo repos.Repository) statusItem {
+	item := statusItem{
+		Owner: repo.Owner,
+		Name:  repo.Name,
+	}
+
+	dir, err := ctrl.rfs.FindCloneDirectory(repo)
+	if err != nil {
+		item.Error = err.Error()
+		return item
+	}
+	item.CloneDir = dir
+
+	item.Status, err = git.GetStatus(ctx, dir)
+	if err != nil {
+		item.Error = err.Error()
+	}
+
+	return item
+}
+
+func statusRow(item statusItem) []string {
+	name := item.Owner + "/" + item.Name
+	if item.Error != "" {
+		return []string{name, "error: " + item.Error, "", "", "", ""}
+	}
+
+	count := func(n int) string {
+		if n == 0 {
+			return ""
+		}
+
+		return strconv.Itoa(n)
+	}
+
+	upstream := "none"
+	if item.Upstream != "" {
+		upstream = item.Upstream
+		if item.Ahead > 0 {
+			upstream += fmt.Sprintf(" ↑%d", item.Ahead)
+		}
+		if item.Behind > 0 {
+			upstream += fmt.Sprintf(" ↓%d", item.Behind)
+		}
+	}
+
+	return []string{name, item.Branch, count(item.Dirty), count(item.Untracked), count(item.Stashes), upstream}
+}
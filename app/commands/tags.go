@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hay-kot/repomgr/app/repos"
+)
+
+// TagAdd attaches the tags to the repository matching the "owner/name" string.
+func (ctrl *Controller) TagAdd(ctx context.Context, name string, tags []string) error {
+	repo, err := ctrl.store.GetByDisplayName(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	return ctrl.store.AddTags(ctx, repo.ID, tags...)
+}
+
+// TagRemove removes the tags from the repository matching the "owner/name" string.
+func (ctrl *Controller) TagRemove(ctx context.Context, name string, tags []string) error {
+	repo, err := ctrl.store.GetByDisplayName(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	return ctrl.store.RemoveTags(ctx, repo.ID, tags...)
+}
+
+// TagNote sets the note for the repository matching the "owner/name" string. An
+// empty note removes the existing note.
+func (ctrl *Controller) TagNote(ctx context.Context, name string, note string) error {
+	repo, err := ctrl.store.GetByDisplayName(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	return ctrl.store.SetNote(ctx, repo.ID, note)
+}
+
+// TagList prints the tags and notes for the repository matching the "owner/name"
+// string. If name is empty, all repositories with tags or notes are printed.
+func (ctrl *Controller) TagList(ctx context.Context, name string) error {
+	var items []repos.Repository
+
+	if name != "" {
+		repo, err := ctrl.store.GetByDisplayName(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		items = []repos.Repository{repo}
+	} else {
+		all, err := ctrl.store.GetAll(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, repo := range all {
+			if len(repo.Tags) > 0 || repo.Note != "" {
+				items = append(items, repo)
+			}
+		}
+	}
+
+	rows := make([][]string, len(items))
+	for i, repo := range items {
+		rows[i] = []string{repo.DisplayName(), strings.Join(repo.Tags, ", "), repo.Note}
+	}
+
+	ctrl.cons.Table([]string{"repository", "tags", "note"}, rows)
+	return nil
+}
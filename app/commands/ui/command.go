@@ -124,6 +124,10 @@ func (c *CommandView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "q", "esc", "ctrl+c":
 			return c.back, nil
 		}
+	case SyncMsg:
+		// the search view is not active but must still receive the sync
+		_, cmd := c.back.Update(msg)
+		cmds = tea.Batch(cmds, cmd)
 	}
 
 	var spincmd tea.Cmd
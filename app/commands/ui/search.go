@@ -1,12 +1,15 @@
 package ui
 
 import (
+	"context"
 	"fmt"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dustin/go-humanize"
 	"github.com/hay-kot/repomgr/app/core/commander"
 	"github.com/hay-kot/repomgr/app/core/repofs"
 	"github.com/hay-kot/repomgr/app/repos"
@@ -16,6 +19,11 @@ import (
 	"github.com/sahilm/fuzzy"
 )
 
+// PinStore persists the pinned state of a repository.
+type PinStore interface {
+	TogglePin(ctx context.Context, repoID int) (bool, error)
+}
+
 // SearchCtrl is the controller/model for the fuzzer finding UI component
 type SearchCtrl struct {
 	*state
@@ -24,6 +32,9 @@ type SearchCtrl struct {
 	repos        []repos.Repository
 	searchLength int
 	selected     int
+	// keepSelected is the id of the repository to select after the next
+	// search, 0 if the selection is kept as is
+	keepSelected int
 
 	// key = filtered index, value = original index
 	indexmap map[int]int
@@ -32,16 +43,124 @@ type SearchCtrl struct {
 	rfs       *repofs.RepoFS
 	commander *commander.Commander
 	keybinds  commander.KeyBindings
+	pins      PinStore
+	lastSync  time.Time
+	syncing   bool
+	syncErr   error
 }
 
-func NewSearchCtrl(r []repos.Repository, rfs *repofs.RepoFS, cmd *commander.Commander) *SearchCtrl {
+func NewSearchCtrl(r []repos.Repository, rfs *repofs.RepoFS, cmd *commander.Commander, pins PinStore) *SearchCtrl {
+	sortPinned(r)
+
 	return &SearchCtrl{
 		state:     &state{},
 		repos:     r,
 		rfs:       rfs,
 		commander: cmd,
 		keybinds:  cmd.Bindings(),
+		pins:      pins,
+	}
+}
+
+// SetLastSync sets the time the cache was last synced, it is shown next to the
+// result count if set.
+func (c *SearchCtrl) SetLastSync(t time.Time) {
+	c.lastSync = t
+}
+
+// SetSyncing marks the cache as being synced in the background, the search
+// view expects a SyncMsg once the sync is finished.
+func (c *SearchCtrl) SetSyncing(syncing bool) {
+	c.syncing = syncing
+}
+
+// SyncMsg is sent to the search view when a background sync of the cache has
+// saved the repositories or failed.
+type SyncMsg struct {
+	// Repos are the repositories in the cache after the sync.
+	Repos []repos.Repository
+	// LastSync is the time the sync started.
+	LastSync time.Time
+	// Err is set if the sync failed, Repos is nil in that case.
+	Err error
+}
+
+// applySync merges the result of a background sync into the repositories.
+func (c *SearchCtrl) applySync(msg SyncMsg) {
+	c.syncing = false
+	c.syncErr = msg.Err
+	if msg.Err != nil {
+		return
+	}
+
+	sortPinned(msg.Repos)
+	c.repos = msg.Repos
+	c.lastSync = msg.LastSync
+
+	// index is positional, so it must be rebuilt for the new repositories
+	c.index = nil
+	c.indexmap = nil
+}
+
+// syncStatus returns the sync state shown next to the result count.
+func (c *SearchCtrl) syncStatus() string {
+	switch {
+	case c.syncing:
+		return "syncing..."
+	case c.syncErr != nil:
+		return "sync failed"
+	case !c.lastSync.IsZero():
+		return "last synced " + humanize.Time(c.lastSync)
+	default:
+		return ""
+	}
+}
+
+// sortPinned sorts the pinned repositories to the top of the list while
+// preserving the existing order otherwise.
+func sortPinned(r []repos.Repository) {
+	slices.SortStableFunc(r, func(a, b repos.Repository) int {
+		return comparePinned(a, b)
+	})
+}
+
+func comparePinned(a, b repos.Repository) int {
+	switch {
+	case a.Pinned == b.Pinned:
+		return 0
+	case a.Pinned:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// togglePin toggles the pinned state of the repository in the store and
+// resorts the repositories so pinned repositories are listed first.
+func (c *SearchCtrl) togglePin(repo repos.Repository) {
+	idx := slices.IndexFunc(c.repos, func(r repos.Repository) bool {
+		return r.ID == repo.ID
+	})
+	if idx == -1 {
+		return
+	}
+
+	pinned, err := c.pins.TogglePin(context.Background(), repo.ID)
+	if err != nil {
+		log.Error().Err(err).Str("repo", repo.DisplayName()).Msg("failed to toggle pin")
+		c.err = err
+		return
 	}
+
+	c.repos[idx].Pinned = pinned
+	sortPinned(c.repos)
+
+	// index is positional, so it must be rebuilt after sorting
+	c.index = nil
+
+	// the repository moved, the cursor follows it once the results are
+	// sorted again
+	c.keepSelected = repo.ID
 }
 
 // Selected returns the active selection by the user, or any empty object
@@ -66,27 +185,102 @@ func (c *SearchCtrl) Selected() repos.Repository {
 	return c.repos[idx]
 }
 
+// parseQuery splits the search string into the fuzzy search query and the tag
+// filters. Tag filters are words in the format of "tag:<name>".
+func parseQuery(str string) (query string, tags []string) {
+	words := strings.Fields(str)
+	rest := make([]string, 0, len(words))
+
+	for _, word := range words {
+		tag, ok := strings.CutPrefix(word, "tag:")
+		if ok {
+			if tag != "" {
+				tags = append(tags, strings.ToLower(tag))
+			}
+			continue
+		}
+
+		rest = append(rest, word)
+	}
+
+	return strings.Join(rest, " "), tags
+}
+
+func hasTags(repo repos.Repository, tags []string) bool {
+	for _, tag := range tags {
+		if !repo.HasTag(tag) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Search runs the same search as the UI without rendering it, returning the
+// matching repositories in display order.
+func (c *SearchCtrl) Search(str string) []repos.Repository {
+	return c.search(str)
+}
+
 // search returns a sorted list of matches uses a fuzzy search algorithm
 func (c *SearchCtrl) search(str string) []repos.Repository {
-	if str == "" {
+	results := c.match(str)
+
+	if c.keepSelected != 0 {
+		idx := slices.IndexFunc(results, func(r repos.Repository) bool {
+			return r.ID == c.keepSelected
+		})
+		if idx != -1 {
+			c.selected = idx
+		}
+
+		c.keepSelected = 0
+	}
+
+	return results
+}
+
+// match returns the repositories matching the search string in display order
+// and updates the indexmap used to resolve the selection.
+func (c *SearchCtrl) match(str string) []repos.Repository {
+	query, tags := parseQuery(str)
+
+	if query == "" && len(tags) == 0 {
 		c.searchLength = len(c.repos)
 		c.indexmap = nil
 		return c.repos
 	}
 
-	if c.index == nil {
-		c.index = make([]string, len(c.repos))
-		for i, repo := range c.repos {
-			c.index[i] = repo.DisplayName()
+	var matches []fuzzy.Match
+	if query == "" {
+		matches = make([]fuzzy.Match, len(c.repos))
+		for i := range c.repos {
+			matches[i] = fuzzy.Match{Index: i}
+		}
+	} else {
+		if c.index == nil {
+			c.index = make([]string, len(c.repos))
+			for i, repo := range c.repos {
+				c.index[i] = repo.DisplayName()
+			}
 		}
+
+		matches = fuzzy.Find(query, c.index)
+		slices.SortStableFunc(matches, func(a, b fuzzy.Match) int {
+			return comparePinned(c.repos[a.Index], c.repos[b.Index])
+		})
 	}
 
-	matches := fuzzy.Find(str, c.index)
 	c.indexmap = make(map[int]int, len(matches))
-	results := make([]repos.Repository, len(matches))
-	for i, match := range matches {
-		results[i] = c.repos[match.Index]
-		c.indexmap[i] = match.Index
+	results := make([]repos.Repository, 0, len(matches))
+	for _, match := range matches {
+		repo := c.repos[match.Index]
+		if !hasTags(repo, tags) {
+			continue
+		}
+
+		c.indexmap[len(results)] = match.Index
+		results = append(results, repo)
 	}
 
 	c.searchLength = len(results)
@@ -126,6 +320,8 @@ func (m *SearchView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
+	case SyncMsg:
+		m.ctrl.applySync(msg)
 	case tea.WindowSizeMsg:
 		m.height = msg.Height
 	case tea.KeyMsg:
@@ -163,6 +359,11 @@ func (m *SearchView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, tea.Quit
 			}
 
+			if action.IsPin() {
+				m.ctrl.togglePin(m.ctrl.Selected())
+				break
+			}
+
 			switch action.Mode {
 			case commander.ModeReadOnly:
 				cmdModel := NewCommandView(action, m)
@@ -226,7 +427,11 @@ func (m *SearchView) View() string {
 	}
 
 	str.WriteString(m.search.View())
-	str.WriteString(styles.Subtle(fmt.Sprintf("\n  %d/%d", len(results), len(m.ctrl.repos))) + "\n")
+	count := fmt.Sprintf("\n  %d/%d", len(results), len(m.ctrl.repos))
+	if status := m.ctrl.syncStatus(); status != "" {
+		count += fmt.Sprintf(" %s %s", icons.Dot, status)
+	}
+	str.WriteString(styles.Subtle(count) + "\n")
 	str.WriteString(m.fmtMatches(results[:determinedMax]))
 
 	// fill remaining height - 1
@@ -266,7 +471,7 @@ func (m SearchView) fmtMatches(repos []repos.Repository) string {
 		}
 	}
 
-	search := m.search.Value()
+	search, _ := parseQuery(m.search.Value())
 
 	str := strings.Builder{}
 	for i, repo := range repos {
@@ -278,6 +483,12 @@ func (m SearchView) fmtMatches(repos []repos.Repository) string {
 			iconSpace  = "    "
 		)
 
+		if repo.Pinned {
+			iconPrefix += styles.AccentRed(icons.Pin) + " "
+		} else {
+			iconPrefix += "  "
+		}
+
 		if repo.IsFork {
 			iconPrefix += styles.Subtle(icons.Fork) + " "
 		} else {
@@ -292,6 +503,20 @@ func (m SearchView) fmtMatches(repos []repos.Repository) string {
 
 		text := "github.com/" + repo.DisplayName() + strings.Repeat(" ", spaces)
 
+		var details []string
+		for _, tag := range repo.Tags {
+			details = append(details, "#"+tag)
+		}
+
+		if repo.Note != "" {
+			details = append(details, repo.Note)
+		}
+
+		detail := ""
+		if len(details) > 0 {
+			detail = styles.Subtle(strings.Join(details, " "))
+		}
+
 		if m.ctrl.selected == i {
 			prefix = styles.HighlightRow(styles.AccentRed(">"))
 			text = styles.HighlightRow(styles.Bold.Render(text))
@@ -304,7 +529,7 @@ func (m SearchView) fmtMatches(repos []repos.Repository) string {
 			}
 		}
 
-		str.WriteString(prefix + iconPrefix + iconSpace + text + "\n")
+		str.WriteString(prefix + iconPrefix + iconSpace + text + detail + "\n")
 	}
 
 	return str.String()
@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hay-kot/repomgr/app/repos"
+	"github.com/matryer/is"
+)
+
+type fakePins map[int]bool
+
+func (p fakePins) TogglePin(_ context.Context, repoID int) (bool, error) {
+	p[repoID] = !p[repoID]
+	return p[repoID], nil
+}
+
+func Test_SearchCtrl_togglePin_KeepsSelection(t *testing.T) {
+	type tcase struct {
+		name  string
+		query string
+	}
+
+	cases := []tcase{
+		{name: "all repositories", query: ""},
+		{name: "fuzzy query", query: "a"},
+		{name: "tag query", query: "tag:go"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			ctrl := &SearchCtrl{
+				state: &state{},
+				pins:  fakePins{},
+				repos: []repos.Repository{
+					{ID: 1, Owner: "hay-kot", Name: "alpha", Tags: []string{"go"}},
+					{ID: 2, Owner: "hay-kot", Name: "beta", Tags: []string{"go"}},
+					{ID: 3, Owner: "hay-kot", Name: "gamma", Tags: []string{"go"}},
+				},
+			}
+
+			results := ctrl.search(tc.query)
+			ctrl.selected = len(results) - 1
+			want := ctrl.Selected()
+
+			// pinning moves the repository to the top
+			ctrl.togglePin(want)
+			ctrl.search(tc.query)
+			is.Equal(ctrl.selected, 0)
+			is.Equal(ctrl.Selected().ID, want.ID)
+
+			// the selection is not moved by later searches
+			ctrl.selected = 1
+			ctrl.search(tc.query)
+			is.Equal(ctrl.selected, 1)
+
+			ctrl.selected = 0
+			ctrl.togglePin(ctrl.Selected())
+			ctrl.search(tc.query)
+			is.Equal(ctrl.Selected().ID, want.ID)
+		})
+	}
+}
@@ -1,8 +1,14 @@
 package console
 
 import (
+	"bufio"
+	"errors"
+	"fmt"
 	"io"
+	"os"
+	"strconv"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/hay-kot/repomgr/internal/icons"
 	"github.com/hay-kot/repomgr/internal/styles"
@@ -15,12 +21,14 @@ type ConsoleOutput interface {
 
 type Console struct {
 	writer io.Writer
+	reader *bufio.Reader
 	color  bool
 }
 
 func NewConsole(writer io.Writer, color bool) *Console {
 	return &Console{
 		writer: writer,
+		reader: bufio.NewReader(os.Stdin),
 		color:  color,
 	}
 }
@@ -56,6 +64,9 @@ func (c *Console) UnknownError(title string, err error) {
 type ListItem struct {
 	StatusOk bool
 	Status   string
+	// Hint is printed below the status of a failed item, e.g. to explain how
+	// to fix it.
+	Hint string
 }
 
 func (c *Console) List(title string, items []ListItem) {
@@ -77,11 +88,135 @@ func (c *Console) List(title string, items []ListItem) {
 		bldr.WriteString(" ")
 		bldr.WriteString(item.Status)
 		bldr.WriteString("\n")
+
+		if !item.StatusOk && item.Hint != "" {
+			bldr.WriteString("     ")
+			bldr.WriteString(styles.Subtle(item.Hint))
+			bldr.WriteString("\n")
+		}
 	}
 
 	c.write(bldr.String())
 }
 
+// Table renders the rows as aligned columns with an uppercase header row.
+func (c *Console) Table(headers []string, rows [][]string) {
+	err := WriteTable(c.writer, headers, rows)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// WriteTable writes the rows to w as aligned columns with an uppercase header
+// row, see Console.Table.
+func WriteTable(w io.Writer, headers []string, rows [][]string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	header := make([]string, len(headers))
+	for i, h := range headers {
+		header[i] = strings.ToUpper(h)
+	}
+
+	_, _ = tw.Write([]byte(" " + strings.Join(header, "\t") + "\n"))
+	for _, row := range rows {
+		_, _ = tw.Write([]byte(" " + strings.Join(row, "\t") + "\n"))
+	}
+
+	return tw.Flush()
+}
+
+// ErrInputClosed is returned by the prompts when the input is closed before
+// the user answered, e.g. when stdin is redirected from /dev/null.
+var ErrInputClosed = errors.New("input closed before an answer was given")
+
+// Confirm prompts the user with a yes/no question and returns true only if the
+// user answers yes. A closed input is treated as no.
+func (c *Console) Confirm(question string) bool {
+	ok, _ := c.ConfirmInput(question)
+	return ok
+}
+
+// ConfirmInput prompts the user with a yes/no question like Confirm, but
+// returns ErrInputClosed if the input is closed.
+func (c *Console) ConfirmInput(question string) (bool, error) {
+	c.write(styles.Bold.Render(styles.Padding.Render(question)) + " [y/N]: ")
+
+	answer, ok := c.readLine()
+	if !ok {
+		return false, ErrInputClosed
+	}
+
+	switch strings.ToLower(answer) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// Prompt asks the user for a value and returns the trimmed answer, or def if
+// the answer is empty. ErrInputClosed is returned if the input is closed.
+func (c *Console) Prompt(question string, def string) (string, error) {
+	str := styles.Bold.Render(styles.Padding.Render(question))
+	if def != "" {
+		str += " " + styles.Subtle("("+def+")")
+	}
+
+	c.write(str + ": ")
+
+	answer, ok := c.readLine()
+	if !ok {
+		return "", ErrInputClosed
+	}
+
+	if answer == "" {
+		return def, nil
+	}
+
+	return answer, nil
+}
+
+// Select asks the user to choose one of the options by number and returns the
+// index of the chosen option. The user is asked again until a valid option is
+// chosen, an empty answer selects def. ErrInputClosed is returned if the input
+// is closed.
+func (c *Console) Select(question string, options []string, def int) (int, error) {
+	c.write(styles.Bold.Render(styles.Padding.Render(question)) + "\n")
+	for i, option := range options {
+		c.write(fmt.Sprintf("   %d) %s\n", i+1, option))
+	}
+
+	for {
+		c.write(styles.Padding.Render("choice") + " " + styles.Subtle(fmt.Sprintf("(%d)", def+1)) + ": ")
+
+		answer, ok := c.readLine()
+		if !ok {
+			return 0, ErrInputClosed
+		}
+
+		if answer == "" {
+			return def, nil
+		}
+
+		n, err := strconv.Atoi(answer)
+		if err == nil && n >= 1 && n <= len(options) {
+			return n - 1, nil
+		}
+	}
+}
+
+// readLine reads a line from the reader, returning false if the input is
+// closed before anything was read.
+func (c *Console) readLine() (string, bool) {
+	answer, err := c.reader.ReadString('\n')
+	if err != nil && answer == "" {
+		c.write("\n")
+		return "", false
+	}
+
+	return strings.TrimSpace(answer), true
+}
+
 func (c *Console) LineBreak() {
 	c.write("\n")
 }
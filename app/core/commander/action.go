@@ -11,6 +11,7 @@ type Action struct {
 	onFinished  []func()
 	isExit      bool
 	exitMessage string
+	isPin       bool
 }
 
 // IsExec returns true if the action is an exec action. It will also
@@ -30,6 +31,12 @@ func (a *Action) IsExit() bool {
 	return a.isExit
 }
 
+// IsPin returns true if the action toggles the pinned state of the
+// repository it was created for.
+func (a *Action) IsPin() bool {
+	return a.isPin
+}
+
 // ExitMessage returns the message to display when the action is an exit action.
 func (a *Action) ExitMessage() string {
 	return a.exitMessage
@@ -55,17 +62,25 @@ func (a *Action) GoRun() <-chan error {
 	errch := make(chan error, 1)
 
 	go func() {
-		err := a.cmd.Run()
+		err := a.Run()
 		if err != nil {
 			errch <- err
 		}
 
-		for _, fn := range a.onFinished {
-			fn()
-		}
-
 		close(errch)
 	}()
 
 	return errch
 }
+
+// Run runs the action in the foreground and blocks until it is finished,
+// returning the resulting error if any.
+func (a *Action) Run() error {
+	err := a.cmd.Run()
+
+	for _, fn := range a.onFinished {
+		fn()
+	}
+
+	return err
+}
@@ -69,6 +69,25 @@ func Test_Action_Run(t *testing.T) {
 	}
 }
 
+func Test_Action_Run_OnFinished(t *testing.T) {
+	is := is.New(t)
+
+	var finished int
+	action := &Action{
+		Mode: ModeReadOnly,
+		cmd: &tActionCommand{
+			run: func() error { return errors.New("failed") },
+			set: func(w io.Writer) {},
+		},
+	}
+
+	action.OnFinished(func() { finished++ })
+
+	err := action.Run()
+	is.True(err != nil)   // want error from command
+	is.Equal(finished, 1) // want finished callbacks to run on error
+}
+
 func Test_Action_IsExec(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -7,6 +7,7 @@ type AppAction string
 const (
 	AppActionFork AppAction = ":GitFork"
 	AppActionExit AppAction = ":Exit"
+	AppActionPin  AppAction = ":Pin"
 )
 
 func (c AppAction) String() string {
@@ -40,7 +41,7 @@ func ParseAppAction(s string) (a AppAction, rest string, ok bool) {
 
 func (c AppAction) IsValid() bool {
 	switch c {
-	case AppActionFork, AppActionExit:
+	case AppActionFork, AppActionExit, AppActionPin:
 		return true
 	default:
 		return false
@@ -27,7 +27,7 @@ func Test_AppAction_IsValid(t *testing.T) {
 		},
 	}
 
-	valid := []AppAction{AppActionFork, AppActionExit}
+	valid := []AppAction{AppActionFork, AppActionExit, AppActionPin}
 	for _, v := range valid {
 		cases = append(cases, tcase{
 			want:   true,
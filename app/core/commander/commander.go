@@ -1,6 +1,7 @@
 package commander
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/hay-kot/repomgr/app/core/repofs"
@@ -34,6 +35,8 @@ func (c *Commander) Bindings() KeyBindings {
 //
 //   - ":Exit" - If the command starts with ":Exit", we return an exit action with the message that
 //     follows the command.
+//   - ":Pin" - If the command starts with ":Pin", we return a pin action that the caller is
+//     responsible for applying to the repository.
 func (c *Commander) GetAction(key string, repo repos.Repository) (action *Action, ok bool) {
 	commandTmpl, ok := c.bindings[key]
 	if !ok {
@@ -64,6 +67,8 @@ func (c *Commander) GetAction(key string, repo repos.Repository) (action *Action
 				isExit:      true,
 				exitMessage: rest,
 			}, true
+		case AppActionPin:
+			return &Action{isPin: true}, true
 		}
 	}
 
@@ -81,6 +86,18 @@ func (c *Commander) GetAction(key string, repo repos.Repository) (action *Action
 	return action, true
 }
 
+// RenderCommand renders the command template of the key binding for the
+// repository without creating an action, returning the template error if the
+// command cannot be rendered.
+func (c *Commander) RenderCommand(key string, repo repos.Repository) (string, error) {
+	commandTmpl, ok := c.bindings[key]
+	if !ok {
+		return "", fmt.Errorf("key binding '%s' not found", key)
+	}
+
+	return c.renderCommandTemplate(repo, commandTmpl.Cmd)
+}
+
 func (c *Commander) renderCommandTemplate(repo repos.Repository, command string) (string, error) {
 	cloneDir, err := c.rfs.FindCloneDirectory(repo)
 	if err != nil {
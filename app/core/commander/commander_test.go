@@ -0,0 +1,52 @@
+package commander
+
+import (
+	"testing"
+
+	"github.com/hay-kot/repomgr/app/core/repofs"
+	"github.com/hay-kot/repomgr/app/repos"
+	"github.com/matryer/is"
+)
+
+func Test_Commander_RenderCommand(t *testing.T) {
+	bindings := KeyBindings{
+		"ctrl+o": {Cmd: "open '{{ .Repo.HTMLURL }}'", Mode: ModeBackground},
+		"ctrl+d": {Cmd: "cd '{{ .CloneDir }}'", Mode: ModeReadOnly},
+		"ctrl+x": {Cmd: "echo {{ .Repo.Missing }}", Mode: ModeReadOnly},
+	}
+
+	rfs := repofs.New(repofs.CloneDirectories{Default: "/src/{{ .Repo.Owner }}/{{ .Repo.Name }}"})
+	cmd := New(bindings, rfs, &ShellCommandBuilder{Shell: "bash", ShellCmdFlag: "-c"})
+
+	repo := repos.Repository{
+		Name:    "repomgr",
+		Owner:   "hay-kot",
+		HTMLURL: "https://github.com/hay-kot/repomgr",
+	}
+
+	tests := []struct {
+		key     string
+		want    string
+		wantErr bool
+	}{
+		{key: "ctrl+o", want: "open 'https://github.com/hay-kot/repomgr'"},
+		{key: "ctrl+d", want: "cd '/src/hay-kot/repomgr'"},
+		{key: "ctrl+x", wantErr: true},
+		{key: "ctrl+z", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			is := is.New(t)
+
+			got, err := cmd.RenderCommand(tt.key, repo)
+			if tt.wantErr {
+				is.True(err != nil) // want render error
+				return
+			}
+
+			is.NoErr(err)
+			is.Equal(got, tt.want)
+		})
+	}
+}
@@ -80,6 +80,7 @@ func (k KeyCommand) IsValid() error {
 	if strings.HasPrefix(k.Cmd, ":") {
 		validoptions := []string{
 			":Exit",
+			":Pin",
 		}
 
 		found := slices.ContainsFunc(validoptions, func(s string) bool {
@@ -24,6 +24,13 @@ func Test_KeyCommand_IsValid(t *testing.T) {
 				Mode: ModeBackground,
 			},
 		},
+		{
+			name: "valid pin command",
+			cmd: KeyCommand{
+				Cmd:  ":Pin",
+				Desc: "toggle pin",
+			},
+		},
 		{
 			name: "invalid mode",
 			cmd: KeyCommand{
@@ -7,10 +7,12 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/hay-kot/repomgr/app/core/commander"
 	"github.com/hay-kot/repomgr/app/core/repofs"
+	"github.com/hay-kot/repomgr/app/core/repostore"
 	"github.com/joho/godotenv"
 	"github.com/rs/zerolog"
 )
@@ -23,7 +25,10 @@ type Config struct {
 	KeyBindings      commander.KeyBindings   `toml:"key_bindings"`
 	Sources          []Source                `toml:"sources"`
 	Database         Database                `toml:"database"`
+	Cache            Cache                   `toml:"cache"`
 	Logs             Logs                    `toml:"logs"`
+	Artifacts        Artifacts               `toml:"artifacts"`
+	Daemon           Daemon                  `toml:"daemon"`
 	CloneDirectories repofs.CloneDirectories `toml:"clone_directories"`
 }
 
@@ -42,6 +47,13 @@ func Default() *Config {
 			File:   "~/config/repomgr/repos.db",
 			Params: "_pragma=busy_timeout=2000&_pragma=journal_mode=WAL&_fk=1",
 		},
+		Artifacts: Artifacts{
+			Types:    []repostore.ArtifactType{},
+			Interval: 30 * time.Minute,
+		},
+		Daemon: Daemon{
+			Interval: 30 * time.Minute,
+		},
 		KeyBindings: commander.NewDefaultKeyBindings(),
 	}
 }
@@ -64,14 +76,28 @@ func New(confpath string, reader io.Reader) (*Config, error) {
 		expandedPaths = append(expandedPaths, ExpandPath(confpath, path))
 	}
 
-	err = godotenv.Load(expandedPaths...)
-	if err != nil {
-		return nil, err
+	// godotenv.Load defaults to ".env" in the working directory when no paths
+	// are given, which fails for configs that do not use dotenvs.
+	if len(expandedPaths) > 0 {
+		err = godotenv.Load(expandedPaths...)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	cfg.Database.File = ExpandPath(confpath, cfg.Database.File)
 	cfg.Logs.File = ExpandPath(confpath, cfg.Logs.File)
 
+	// the daemon files default to the directory of the database
+	if cfg.Daemon.LockFile == "" {
+		cfg.Daemon.LockFile = filepath.Join(filepath.Dir(cfg.Database.File), "daemon.lock")
+	}
+	if cfg.Daemon.StatusFile == "" {
+		cfg.Daemon.StatusFile = filepath.Join(filepath.Dir(cfg.Database.File), "daemon.json")
+	}
+	cfg.Daemon.LockFile = ExpandPath(confpath, cfg.Daemon.LockFile)
+	cfg.Daemon.StatusFile = ExpandPath(confpath, cfg.Daemon.StatusFile)
+
 	cfg.CloneDirectories.Default = ExpandPath(confpath, cfg.CloneDirectories.Default)
 	for i := range cfg.CloneDirectories.Matchers {
 		cfg.CloneDirectories.
@@ -111,6 +137,9 @@ func (c Config) Validate() error {
 	validators := []validator{
 		c.KeyBindings,
 		c.Database,
+		c.Cache,
+		c.Artifacts,
+		c.Daemon,
 		c.CloneDirectories,
 	}
 
@@ -142,6 +171,68 @@ type Logs struct {
 	Format string        `toml:"format"`
 }
 
+// Artifacts configures the enrichment data that is fetched for each repository
+// and cached in the database.
+type Artifacts struct {
+	// Types is the list of artifacts that are fetched for every repository.
+	Types []repostore.ArtifactType `toml:"types"`
+	// Background enables refreshing stale artifacts while the search UI is open.
+	Background bool `toml:"background"`
+	// Interval is the time between background refreshes.
+	Interval time.Duration `toml:"interval"`
+}
+
+func (a Artifacts) Validate() error {
+	for _, t := range a.Types {
+		if err := t.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if a.Background && a.Interval <= 0 {
+		return fmt.Errorf("artifact refresh interval must be greater than 0")
+	}
+
+	return nil
+}
+
+// Cache configures the repository cache.
+type Cache struct {
+	// TTL is the maximum age of the cache when the search UI is opened, an
+	// older cache is synced in the background. 0 disables the refresh.
+	TTL time.Duration `toml:"ttl"`
+}
+
+func (c Cache) Validate() error {
+	if c.TTL < 0 {
+		return fmt.Errorf("cache ttl must not be negative")
+	}
+
+	return nil
+}
+
+// Daemon configures the background sync started with `repomgr daemon`.
+type Daemon struct {
+	// Interval is the time between syncs.
+	Interval time.Duration `toml:"interval"`
+	// Fetch fetches the remotes of every cloned repository after each sync.
+	Fetch bool `toml:"fetch"`
+	// LockFile ensures only a single daemon runs, defaults to daemon.lock next
+	// to the database.
+	LockFile string `toml:"lock_file"`
+	// StatusFile is written after every sync, defaults to daemon.json next to
+	// the database.
+	StatusFile string `toml:"status_file"`
+}
+
+func (d Daemon) Validate() error {
+	if d.Interval <= 0 {
+		return fmt.Errorf("daemon interval must be greater than 0")
+	}
+
+	return nil
+}
+
 type Database struct {
 	File   string `toml:"file"`
 	Params string `toml:"params"`
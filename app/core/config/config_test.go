@@ -0,0 +1,42 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func Test_New(t *testing.T) {
+	is := is.New(t)
+
+	// no dotenvs and no log file, neither of which should be required
+	const conf = `
+[database]
+file = "./repos.db"
+
+[[sources]]
+type = "github"
+username = "hay-kot"
+
+[clone_directories]
+default = "./projects/{{ .Repo.Name }}"
+`
+
+	dir := t.TempDir()
+
+	confpath := filepath.Join(dir, "config.toml")
+	cfg, err := New(confpath, strings.NewReader(conf))
+	is.NoErr(err)
+
+	is.Equal(cfg.Database.File, filepath.Join(dir, "repos.db"))
+	is.Equal(cfg.Logs.File, "")
+	is.Equal(cfg.CloneDirectories.Default, filepath.Join(dir, "projects/{{ .Repo.Name }}"))
+
+	// daemon files default to the database directory
+	is.Equal(cfg.Daemon.LockFile, filepath.Join(dir, "daemon.lock"))
+	is.Equal(cfg.Daemon.StatusFile, filepath.Join(dir, "daemon.json"))
+	is.Equal(cfg.Daemon.Interval, 30*time.Minute)
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	_ "embed"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/BurntSushi/toml"
+)
+
+//go:embed init.toml
+var initTemplate string
+
+// DefaultDotEnvFile is the dotenv file, relative to the config file, written by
+// the init wizard when tokens are read from a dotenv file.
+const DefaultDotEnvFile = "./.env"
+
+// Init are the answers used to render a new configuration file.
+type Init struct {
+	Shell          string
+	Sources        []Source
+	CloneDirectory string
+	// DotEnv adds DotEnvFile to the dotenvs of the config.
+	DotEnv     bool
+	DotEnvFile string
+}
+
+// RenderInit renders a commented configuration file from the answers. The
+// rendered file is decoded and validated the same way as when it is loaded so
+// an invalid configuration is never written.
+func RenderInit(i Init) (string, error) {
+	if i.DotEnvFile == "" {
+		i.DotEnvFile = DefaultDotEnvFile
+	}
+
+	tmpl, err := template.New("config").
+		Funcs(template.FuncMap{"quote": strconv.Quote}).
+		Parse(initTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	bldr := strings.Builder{}
+	err = tmpl.Execute(&bldr, i)
+	if err != nil {
+		return "", err
+	}
+
+	cfg := Default()
+	_, err = toml.Decode(bldr.String(), cfg)
+	if err != nil {
+		return "", err
+	}
+
+	err = cfg.Validate()
+	if err != nil {
+		return "", err
+	}
+
+	return bldr.String(), nil
+}
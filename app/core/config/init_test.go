@@ -0,0 +1,69 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/hay-kot/repomgr/app/core/commander"
+	"github.com/matryer/is"
+)
+
+func Test_RenderInit(t *testing.T) {
+	is := is.New(t)
+
+	got, err := RenderInit(Init{
+		Shell: "zsh",
+		Sources: []Source{
+			{Type: SourceTypeGithub, Username: "hay-kot", TokenKey: "env:GITHUB_TOKEN"},
+			{Type: SourceTypeGithub, Username: "mealie-recipes"},
+		},
+		CloneDirectory: "~/src/{{ .Repo.Owner }}/{{ .Repo.Name }}",
+		DotEnv:         true,
+	})
+	is.NoErr(err)
+
+	cfg := Default()
+	_, err = toml.Decode(got, cfg)
+	is.NoErr(err)
+
+	is.Equal(cfg.Shell, "zsh")
+	is.Equal(cfg.DotEnvs, []string{DefaultDotEnvFile})
+	is.Equal(len(cfg.Sources), 2)
+	is.Equal(cfg.Sources[0].TokenKey, "env:GITHUB_TOKEN")
+	is.Equal(cfg.Sources[1].TokenKey, "")
+	is.Equal(cfg.CloneDirectories.Default, "~/src/{{ .Repo.Owner }}/{{ .Repo.Name }}")
+	is.Equal(cfg.Cache.TTL, 24*time.Hour)
+	is.Equal(len(cfg.CloneDirectories.Matchers), 0)                        // want matchers example commented out
+	is.True(strings.Contains(got, "# [key_bindings]"))                     // want key bindings example
+	is.Equal(len(cfg.KeyBindings), len(commander.NewDefaultKeyBindings())) // want default key bindings
+}
+
+func Test_RenderInit_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		init Init
+	}{
+		{
+			name: "no sources",
+			init: Init{Shell: "bash", CloneDirectory: "~/src/{{ .Repo.Name }}"},
+		},
+		{
+			name: "no clone directory",
+			init: Init{
+				Shell:   "bash",
+				Sources: []Source{{Type: SourceTypeGithub, Username: "hay-kot"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			is := is.New(t)
+
+			_, err := RenderInit(tt.init)
+			is.True(err != nil) // want validation error
+		})
+	}
+}
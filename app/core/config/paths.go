@@ -3,10 +3,11 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 func ExpandPath(confpath, input string) string {
-	if input[:2] == "~/" {
+	if strings.HasPrefix(input, "~/") {
 		home, err := os.UserHomeDir()
 		if err != nil {
 			panic(err)
@@ -14,7 +15,7 @@ func ExpandPath(confpath, input string) string {
 		input = home + input[1:]
 	}
 
-	if input[:2] == "./" {
+	if strings.HasPrefix(input, "./") {
 		confdir := filepath.Dir(confpath)
 		input = confdir + input[1:]
 	}
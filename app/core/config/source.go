@@ -29,6 +29,17 @@ type Source struct {
 	TokenKey string     `toml:"token"`
 }
 
+// ID returns the identifier of the source in the format of "type/username". It
+// is stored alongside every repository synced from the source.
+func (s Source) ID() string {
+	return s.Type.String() + "/" + s.Username
+}
+
+// Matches returns true if str is the ID or the username of the source.
+func (s Source) Matches(str string) bool {
+	return str == s.ID() || str == s.Username
+}
+
 func (s Source) Token() string {
 	if strings.HasPrefix(s.TokenKey, "env:") {
 		return os.Getenv(strings.TrimPrefix(s.TokenKey, "env:"))
@@ -0,0 +1,110 @@
+// Package daemon provides the lock and status files used to coordinate the
+// background sync daemon with the other commands.
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// ErrLocked is returned by Acquire when another running process holds the lock.
+var ErrLocked = errors.New("daemon is already running")
+
+// Lock is a lock file containing the pid of the process holding it.
+type Lock struct {
+	path string
+}
+
+// Acquire creates the lock file at path. If the lock file exists and the
+// process that created it is still running ErrLocked is returned, otherwise
+// the stale lock file is replaced.
+func Acquire(path string) (*Lock, error) {
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	// a single retry is enough to replace a stale lock, if the lock is taken
+	// again in between another process won the race.
+	for attempt := 0; attempt < 2; attempt++ {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			_, err = f.WriteString(strconv.Itoa(os.Getpid()))
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+
+			if err != nil {
+				_ = os.Remove(path)
+				return nil, err
+			}
+
+			return &Lock{path: path}, nil
+		}
+
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+
+		pid, err := LockHolder(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if pid != 0 {
+			return nil, fmt.Errorf("%w (pid %d)", ErrLocked, pid)
+		}
+
+		err = os.Remove(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	return nil, ErrLocked
+}
+
+// Release removes the lock file.
+func (l *Lock) Release() error {
+	return os.Remove(l.path)
+}
+
+// LockHolder returns the pid of the running process holding the lock file at
+// path, or 0 if the lock file does not exist or is stale.
+func LockHolder(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || !processRunning(pid) {
+		return 0, nil
+	}
+
+	return pid, nil
+}
+
+// processRunning returns true if a process with the pid exists. Platforms that
+// do not support signal 0 always report the process as stopped.
+func processRunning(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, os.ErrPermission)
+}
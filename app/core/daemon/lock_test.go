@@ -0,0 +1,69 @@
+package daemon
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func Test_Acquire(t *testing.T) {
+	is := is.New(t)
+
+	path := filepath.Join(t.TempDir(), "daemon.lock")
+
+	lock, err := Acquire(path)
+	is.NoErr(err)
+
+	pid, err := LockHolder(path)
+	is.NoErr(err)
+	is.Equal(pid, os.Getpid())
+
+	_, err = Acquire(path)
+	is.True(errors.Is(err, ErrLocked))
+
+	is.NoErr(lock.Release())
+
+	pid, err = LockHolder(path)
+	is.NoErr(err)
+	is.Equal(pid, 0)
+
+	lock, err = Acquire(path)
+	is.NoErr(err)
+	is.NoErr(lock.Release())
+}
+
+func Test_Acquire_StaleLock(t *testing.T) {
+	type tcase struct {
+		name    string
+		content string
+	}
+
+	cases := []tcase{
+		{name: "invalid pid", content: "not a pid"},
+		{name: "empty", content: ""},
+		// pid_max on linux is at most 2^22, this pid can never be running
+		{name: "stopped process", content: strconv.Itoa(1 << 30)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			path := filepath.Join(t.TempDir(), "daemon.lock")
+			is.NoErr(os.WriteFile(path, []byte(tc.content), 0o644))
+
+			lock, err := Acquire(path)
+			is.NoErr(err)
+
+			pid, err := LockHolder(path)
+			is.NoErr(err)
+			is.Equal(pid, os.Getpid())
+
+			is.NoErr(lock.Release())
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Status is the state of the cache sync written to the status file after
+// every sync, it is read by the search UI to show when the cache was last
+// synced.
+type Status struct {
+	// PID is the pid of the running daemon, 0 once it exits or if no daemon
+	// was started. A manual sync keeps the pid of a running daemon.
+	PID int `json:"pid"`
+	// LastSync is the time the last successful sync started.
+	LastSync time.Time `json:"last_sync"`
+	// LastError is the error of the last sync, empty if it succeeded.
+	LastError string `json:"last_error,omitempty"`
+	// Repositories is the number of repositories synced by the last
+	// successful sync.
+	Repositories int `json:"repositories"`
+	// NextSync is the time of the next scheduled sync of the running daemon,
+	// zero if no daemon is running.
+	NextSync time.Time `json:"next_sync"`
+}
+
+// IsStale returns true if the last successful sync is older than the ttl or
+// the cache has never been synced.
+func (s Status) IsStale(ttl time.Duration, now time.Time) bool {
+	return s.LastSync.IsZero() || now.Sub(s.LastSync) > ttl
+}
+
+// ReadStatus reads the status file at path. A missing status file returns the
+// zero Status.
+func ReadStatus(path string) (Status, error) {
+	var s Status
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return s, nil
+		}
+		return s, err
+	}
+
+	err = json.Unmarshal(data, &s)
+	return s, err
+}
+
+// WriteStatus writes the status file at path. The file is replaced atomically
+// so readers never see a partial status.
+func WriteStatus(path string, s Status) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	err = os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+
+	return nil
+}
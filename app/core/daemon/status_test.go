@@ -0,0 +1,63 @@
+package daemon
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func Test_Status_ReadWrite(t *testing.T) {
+	is := is.New(t)
+
+	path := filepath.Join(t.TempDir(), "status", "daemon.json")
+
+	got, err := ReadStatus(path)
+	is.NoErr(err)
+	is.True(got.LastSync.IsZero())
+
+	want := Status{
+		PID:          42,
+		LastSync:     time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		LastError:    "sync failed",
+		Repositories: 12,
+		NextSync:     time.Date(2024, 1, 2, 3, 34, 5, 0, time.UTC),
+	}
+
+	is.NoErr(WriteStatus(path, want))
+
+	got, err = ReadStatus(path)
+	is.NoErr(err)
+	is.Equal(got, want)
+
+	matches, err := filepath.Glob(filepath.Join(filepath.Dir(path), ".*"))
+	is.NoErr(err)
+	is.Equal(len(matches), 0) // temporary files are removed
+}
+
+func Test_Status_IsStale(t *testing.T) {
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	type tcase struct {
+		name     string
+		lastSync time.Time
+		want     bool
+	}
+
+	cases := []tcase{
+		{name: "never synced", lastSync: time.Time{}, want: true},
+		{name: "recent", lastSync: now.Add(-time.Hour), want: false},
+		{name: "at ttl", lastSync: now.Add(-24 * time.Hour), want: false},
+		{name: "older than ttl", lastSync: now.Add(-25 * time.Hour), want: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			s := Status{LastSync: tc.lastSync}
+			is.Equal(s.IsStale(24*time.Hour, now), tc.want)
+		})
+	}
+}
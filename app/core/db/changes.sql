@@ -0,0 +1,24 @@
+-- name: RepoByRemoteID :many
+SELECT 
+  * 
+FROM  
+  repository 
+WHERE 
+  remote_id = ?;
+
+-- name: ChangeCreate :exec
+INSERT INTO 
+  repository_change (repository_id, display_name, kind, old_value, new_value, created_at) 
+VALUES 
+  (?, ?, ?, ?, ?, ?);
+
+-- name: ChangesSince :many
+SELECT 
+  * 
+FROM  
+  repository_change 
+WHERE 
+  created_at >= ? 
+ORDER BY 
+  created_at DESC, 
+  id DESC;
@@ -0,0 +1,91 @@
+-- name: RepoDelete :exec
+DELETE FROM 
+  repository 
+WHERE 
+  id = ?;
+
+-- name: ReposBySource :many
+SELECT 
+  * 
+FROM  
+  repository 
+WHERE 
+  source = ?;
+
+-- name: ReposSyncedBefore :many
+SELECT 
+  * 
+FROM  
+  repository 
+WHERE 
+  synced_at < ?;
+
+-- name: ArtifactsCountOrphaned :one
+SELECT 
+  COUNT(*) 
+FROM  
+  repository_artifact 
+WHERE 
+  repository_id NOT IN (SELECT id FROM repository);
+
+-- name: TagsCountOrphaned :one
+SELECT 
+  COUNT(*) 
+FROM  
+  repository_tag 
+WHERE 
+  repository_id NOT IN (SELECT id FROM repository);
+
+-- name: ChangesCountOrphaned :one
+SELECT 
+  COUNT(*) 
+FROM  
+  repository_change 
+WHERE 
+  repository_id NOT IN (SELECT id FROM repository);
+
+-- name: NotesCountOrphaned :one
+SELECT 
+  COUNT(*) 
+FROM  
+  repository_note 
+WHERE 
+  repository_id NOT IN (SELECT id FROM repository);
+
+-- name: PinsCountOrphaned :one
+SELECT 
+  COUNT(*) 
+FROM  
+  repository_pin 
+WHERE 
+  repository_id NOT IN (SELECT id FROM repository);
+
+-- name: ArtifactsDeleteOrphaned :execrows
+DELETE FROM 
+  repository_artifact 
+WHERE 
+  repository_id NOT IN (SELECT id FROM repository);
+
+-- name: ChangesDeleteOrphaned :execrows
+DELETE FROM 
+  repository_change 
+WHERE 
+  repository_id NOT IN (SELECT id FROM repository);
+
+-- name: TagsDeleteOrphaned :execrows
+DELETE FROM 
+  repository_tag 
+WHERE 
+  repository_id NOT IN (SELECT id FROM repository);
+
+-- name: NotesDeleteOrphaned :execrows
+DELETE FROM 
+  repository_note 
+WHERE 
+  repository_id NOT IN (SELECT id FROM repository);
+
+-- name: PinsDeleteOrphaned :execrows
+DELETE FROM 
+  repository_pin 
+WHERE 
+  repository_id NOT IN (SELECT id FROM repository);
@@ -1,8 +1,70 @@
 package migrations
 
 import (
-	_ "embed"
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"slices"
 )
 
 //go:embed sql/schema.sql
 var Schema string
+
+//go:embed sql/migrations/*.sql
+var migrationsFS embed.FS
+
+// Migrate creates the base schema and applies any migrations that have not yet
+// been applied to the database. Applied migrations are tracked with the sqlite
+// user_version pragma, where the version is the number of applied migrations.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, Schema)
+	if err != nil {
+		return err
+	}
+
+	files, err := fs.Glob(migrationsFS, "sql/migrations/*.sql")
+	if err != nil {
+		return err
+	}
+	slices.Sort(files)
+
+	var version int
+	err = db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version)
+	if err != nil {
+		return err
+	}
+
+	for i := version; i < len(files); i++ {
+		stmt, err := migrationsFS.ReadFile(files[i])
+		if err != nil {
+			return err
+		}
+
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, string(stmt))
+		if err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("failed to apply migration %s: %w", files[i], err)
+		}
+
+		// pragmas do not support bound parameters
+		_, err = tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", i+1))
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+
+		err = tx.Commit()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	_ "modernc.org/sqlite"
+)
+
+func Test_Migrate_BackfillsSyncedAt(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	is.NoErr(err)
+	db.SetMaxOpenConns(1)
+
+	// a database created before the migrations existed
+	_, err = db.ExecContext(ctx, Schema)
+	is.NoErr(err)
+
+	_, err = db.ExecContext(ctx, `INSERT INTO repository
+		(remote_id, name, username, description, html_url, clone_url, clone_ssh_url, is_fork, fork_url)
+		VALUES ('1', 'repomgr', 'hay-kot', '', '', '', '', FALSE, '')`)
+	is.NoErr(err)
+
+	is.NoErr(Migrate(ctx, db))
+
+	var syncedAt int64
+	err = db.QueryRowContext(ctx, "SELECT synced_at FROM repository WHERE remote_id = '1'").Scan(&syncedAt)
+	is.NoErr(err)
+	is.True(time.Since(time.Unix(syncedAt, 0)) < time.Minute) // existing repositories are not stale
+}
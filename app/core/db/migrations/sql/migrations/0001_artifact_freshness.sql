@@ -0,0 +1,3 @@
+ALTER TABLE repository_artifact ADD COLUMN fetched_at INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE repository_artifact ADD COLUMN expires_at INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE repository_artifact ADD COLUMN content_hash TEXT NOT NULL DEFAULT '';
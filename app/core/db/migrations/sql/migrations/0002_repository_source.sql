@@ -0,0 +1,6 @@
+ALTER TABLE repository ADD COLUMN source TEXT NOT NULL DEFAULT '';
+ALTER TABLE repository ADD COLUMN synced_at INTEGER NOT NULL DEFAULT 0;
+
+-- existing repositories were synced before the column existed, without a
+-- backfill they would all be pruned as stale before the next sync
+UPDATE repository SET synced_at = CAST(strftime('%s', 'now') AS INTEGER);
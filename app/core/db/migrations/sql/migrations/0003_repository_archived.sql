@@ -0,0 +1,4 @@
+-- is_archived is NULL until the archived state of the repository is synced.
+-- Defaulting to FALSE would log every repository that was archived before the
+-- upgrade as newly archived on the first sync.
+ALTER TABLE repository ADD COLUMN is_archived BOOLEAN;
@@ -19,3 +19,48 @@ CREATE TABLE IF NOT EXISTS repository_artifact (
   FOREIGN KEY (repository_id) REFERENCES repository(id) ON DELETE CASCADE,
   UNIQUE(repository_id, data_type)
 );
+
+CREATE TABLE IF NOT EXISTS repository_pin (
+  repository_id INTEGER PRIMARY KEY,
+  created_at    DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (repository_id) REFERENCES repository(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS repository_tag (
+  id            INTEGER PRIMARY KEY,
+  repository_id INTEGER NOT NULL,
+  tag           TEXT    NOT NULL,
+  FOREIGN KEY (repository_id) REFERENCES repository(id) ON DELETE CASCADE,
+  UNIQUE(repository_id, tag)
+);
+
+CREATE TABLE IF NOT EXISTS repository_note (
+  repository_id INTEGER PRIMARY KEY,
+  note          TEXT    NOT NULL,
+  FOREIGN KEY (repository_id) REFERENCES repository(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS repository_change (
+  id            INTEGER PRIMARY KEY,
+  repository_id INTEGER NOT NULL,
+  display_name  TEXT    NOT NULL,
+  kind          TEXT    NOT NULL,
+  old_value     TEXT    NOT NULL,
+  new_value     TEXT    NOT NULL,
+  created_at    INTEGER NOT NULL,
+  FOREIGN KEY (repository_id) REFERENCES repository(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_repository_change_created_at ON repository_change(created_at);
+
+CREATE TABLE IF NOT EXISTS clone_relocation (
+  id         INTEGER PRIMARY KEY,
+  batch      INTEGER NOT NULL,
+  from_path  TEXT    NOT NULL,
+  to_path    TEXT    NOT NULL,
+  created_at INTEGER NOT NULL,
+  moved_at   INTEGER,
+  undone_at  INTEGER
+);
+
+CREATE INDEX IF NOT EXISTS idx_clone_relocation_batch ON clone_relocation(batch);
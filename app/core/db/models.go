@@ -4,6 +4,21 @@
 
 package db
 
+import (
+	"database/sql"
+	"time"
+)
+
+type CloneRelocation struct {
+	ID        int64
+	Batch     int64
+	FromPath  string
+	ToPath    string
+	CreatedAt int64
+	MovedAt   sql.NullInt64
+	UndoneAt  sql.NullInt64
+}
+
 type Repository struct {
 	ID          int64
 	RemoteID    string
@@ -15,6 +30,9 @@ type Repository struct {
 	CloneSshUrl string
 	IsFork      bool
 	ForkUrl     string
+	Source      string
+	SyncedAt    int64
+	IsArchived  sql.NullBool
 }
 
 type RepositoryArtifact struct {
@@ -22,4 +40,33 @@ type RepositoryArtifact struct {
 	DataType     string
 	Data         []byte
 	RepositoryID int64
+	FetchedAt    int64
+	ExpiresAt    int64
+	ContentHash  string
+}
+
+type RepositoryChange struct {
+	ID           int64
+	RepositoryID int64
+	DisplayName  string
+	Kind         string
+	OldValue     string
+	NewValue     string
+	CreatedAt    int64
+}
+
+type RepositoryNote struct {
+	RepositoryID int64
+	Note         string
+}
+
+type RepositoryPin struct {
+	RepositoryID int64
+	CreatedAt    time.Time
+}
+
+type RepositoryTag struct {
+	ID           int64
+	RepositoryID int64
+	Tag          string
 }
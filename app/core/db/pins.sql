@@ -0,0 +1,27 @@
+-- name: PinCreate :exec
+INSERT INTO 
+  repository_pin (repository_id) 
+VALUES 
+  (?) 
+ON CONFLICT (repository_id) 
+DO NOTHING;
+
+-- name: PinDelete :exec
+DELETE FROM 
+  repository_pin 
+WHERE 
+  repository_id = ?;
+
+-- name: PinExists :one
+SELECT 
+  COUNT(*) > 0 
+FROM  
+  repository_pin 
+WHERE 
+  repository_id = ?;
+
+-- name: PinsGetAll :many
+SELECT 
+  repository_id 
+FROM  
+  repository_pin;
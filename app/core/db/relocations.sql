@@ -0,0 +1,43 @@
+-- name: RelocationCreate :one
+INSERT INTO 
+  clone_relocation (batch, from_path, to_path, created_at) 
+VALUES 
+  (?, ?, ?, ?) 
+RETURNING 
+  *;
+
+-- name: RelocationMarkMoved :exec
+UPDATE 
+  clone_relocation 
+SET 
+  moved_at = ? 
+WHERE 
+  id = ?;
+
+-- name: RelocationMarkUndone :exec
+UPDATE 
+  clone_relocation 
+SET 
+  undone_at = ? 
+WHERE 
+  id = ?;
+
+-- name: RelocationsUndoable :many
+SELECT 
+  * 
+FROM  
+  clone_relocation 
+WHERE 
+  batch = (
+    SELECT 
+      MAX(batch) 
+    FROM 
+      clone_relocation 
+    WHERE 
+      moved_at IS NOT NULL 
+      AND undone_at IS NULL
+  ) 
+  AND moved_at IS NOT NULL 
+  AND undone_at IS NULL 
+ORDER BY 
+  id DESC;
@@ -9,10 +9,13 @@ INSERT INTO
       clone_url, 
       clone_ssh_url, 
       is_fork,
-      fork_url
+      fork_url,
+      source,
+      synced_at,
+      is_archived
   )
 VALUES 
-  (?, ?, ?, ?, ?, ?, ?, ?, ?) 
+  (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) 
 RETURNING 
   *;  
 
@@ -27,10 +30,13 @@ INSERT INTO
       clone_url, 
       clone_ssh_url, 
       is_fork,
-      fork_url  
+      fork_url,
+      source,
+      synced_at,
+      is_archived
   ) 
 VALUES 
-  (?, ?, ?, ?, ?, ?, ?, ?, ?) 
+  (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) 
 ON CONFLICT (remote_id) 
 DO UPDATE SET 
   name = EXCLUDED.name, 
@@ -39,7 +45,10 @@ DO UPDATE SET
   html_url = EXCLUDED.html_url, 
   clone_url = EXCLUDED.clone_url, 
   clone_ssh_url = EXCLUDED.clone_ssh_url, 
-  is_fork = EXCLUDED.is_fork
+  is_fork = EXCLUDED.is_fork,
+  source = EXCLUDED.source,
+  synced_at = EXCLUDED.synced_at,
+  is_archived = EXCLUDED.is_archived
 RETURNING *;
 
 -- name: ReposByUsernameLike :many
@@ -83,12 +92,15 @@ WHERE
 
 -- name: RepoUpsertArtifact :one 
 INSERT INTO 
-  repository_artifact (repository_id, data_type, data)  
+  repository_artifact (repository_id, data_type, data, fetched_at, expires_at, content_hash)  
 VALUES 
-  (?, ?, ?)
+  (?, ?, ?, ?, ?, ?)
 ON CONFLICT (repository_id, data_type)
 DO UPDATE SET 
-  data = EXCLUDED.data  
+  data = EXCLUDED.data,
+  fetched_at = EXCLUDED.fetched_at,
+  expires_at = EXCLUDED.expires_at,
+  content_hash = EXCLUDED.content_hash
 RETURNING
   *;
 
@@ -99,5 +111,25 @@ SET
   data = ?
 WHERE 
   repository_id = ?
-  AND data_type = ?
+  AND data_type = ?;
 
+-- name: RepoByOwnerName :one
+SELECT 
+  * 
+FROM  
+  repository 
+WHERE 
+  username = ? 
+  AND name = ?;
+
+-- name: ReposWithStaleArtifact :many
+SELECT 
+  repository.* 
+FROM 
+  repository 
+  LEFT JOIN repository_artifact 
+    ON repository_artifact.repository_id = repository.id 
+    AND repository_artifact.data_type = ? 
+WHERE 
+  repository_artifact.id IS NULL 
+  OR repository_artifact.expires_at <= ?;
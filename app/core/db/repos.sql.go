@@ -7,11 +7,12 @@ package db
 
 import (
 	"context"
+	"database/sql"
 )
 
 const repoArtifactByType = `-- name: RepoArtifactByType :many
 SELECT 
-  id, data_type, data, repository_id 
+  id, data_type, data, repository_id, fetched_at, expires_at, content_hash 
 FROM  
   repository_artifact 
 WHERE 
@@ -38,6 +39,9 @@ func (q *Queries) RepoArtifactByType(ctx context.Context, arg RepoArtifactByType
 			&i.DataType,
 			&i.Data,
 			&i.RepositoryID,
+			&i.FetchedAt,
+			&i.ExpiresAt,
+			&i.ContentHash,
 		); err != nil {
 			return nil, err
 		}
@@ -54,7 +58,7 @@ func (q *Queries) RepoArtifactByType(ctx context.Context, arg RepoArtifactByType
 
 const repoArtifacts = `-- name: RepoArtifacts :many
 SELECT
-  id, data_type, data, repository_id 
+  id, data_type, data, repository_id, fetched_at, expires_at, content_hash 
 FROM  
   repository_artifact 
 WHERE 
@@ -75,6 +79,9 @@ func (q *Queries) RepoArtifacts(ctx context.Context, repositoryID int64) ([]Repo
 			&i.DataType,
 			&i.Data,
 			&i.RepositoryID,
+			&i.FetchedAt,
+			&i.ExpiresAt,
+			&i.ContentHash,
 		); err != nil {
 			return nil, err
 		}
@@ -89,6 +96,42 @@ func (q *Queries) RepoArtifacts(ctx context.Context, repositoryID int64) ([]Repo
 	return items, nil
 }
 
+const repoByOwnerName = `-- name: RepoByOwnerName :one
+SELECT 
+  id, remote_id, name, username, description, html_url, clone_url, clone_ssh_url, is_fork, fork_url, source, synced_at, is_archived 
+FROM  
+  repository 
+WHERE 
+  username = ? 
+  AND name = ?
+`
+
+type RepoByOwnerNameParams struct {
+	Username string
+	Name     string
+}
+
+func (q *Queries) RepoByOwnerName(ctx context.Context, arg RepoByOwnerNameParams) (Repository, error) {
+	row := q.db.QueryRowContext(ctx, repoByOwnerName, arg.Username, arg.Name)
+	var i Repository
+	err := row.Scan(
+		&i.ID,
+		&i.RemoteID,
+		&i.Name,
+		&i.Username,
+		&i.Description,
+		&i.HtmlUrl,
+		&i.CloneUrl,
+		&i.CloneSshUrl,
+		&i.IsFork,
+		&i.ForkUrl,
+		&i.Source,
+		&i.SyncedAt,
+		&i.IsArchived,
+	)
+	return i, err
+}
+
 const repoCreate = `-- name: RepoCreate :one
 INSERT INTO 
   repository (
@@ -100,12 +143,15 @@ INSERT INTO
       clone_url, 
       clone_ssh_url, 
       is_fork,
-      fork_url
+      fork_url,
+      source,
+      synced_at,
+      is_archived
   )
 VALUES 
-  (?, ?, ?, ?, ?, ?, ?, ?, ?) 
+  (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) 
 RETURNING 
-  id, remote_id, name, username, description, html_url, clone_url, clone_ssh_url, is_fork, fork_url
+  id, remote_id, name, username, description, html_url, clone_url, clone_ssh_url, is_fork, fork_url, source, synced_at, is_archived
 `
 
 type RepoCreateParams struct {
@@ -118,6 +164,9 @@ type RepoCreateParams struct {
 	CloneSshUrl string
 	IsFork      bool
 	ForkUrl     string
+	Source      string
+	SyncedAt    int64
+	IsArchived  sql.NullBool
 }
 
 func (q *Queries) RepoCreate(ctx context.Context, arg RepoCreateParams) (Repository, error) {
@@ -131,6 +180,9 @@ func (q *Queries) RepoCreate(ctx context.Context, arg RepoCreateParams) (Reposit
 		arg.CloneSshUrl,
 		arg.IsFork,
 		arg.ForkUrl,
+		arg.Source,
+		arg.SyncedAt,
+		arg.IsArchived,
 	)
 	var i Repository
 	err := row.Scan(
@@ -144,6 +196,9 @@ func (q *Queries) RepoCreate(ctx context.Context, arg RepoCreateParams) (Reposit
 		&i.CloneSshUrl,
 		&i.IsFork,
 		&i.ForkUrl,
+		&i.Source,
+		&i.SyncedAt,
+		&i.IsArchived,
 	)
 	return i, err
 }
@@ -180,10 +235,13 @@ INSERT INTO
       clone_url, 
       clone_ssh_url, 
       is_fork,
-      fork_url  
+      fork_url,
+      source,
+      synced_at,
+      is_archived
   ) 
 VALUES 
-  (?, ?, ?, ?, ?, ?, ?, ?, ?) 
+  (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) 
 ON CONFLICT (remote_id) 
 DO UPDATE SET 
   name = EXCLUDED.name, 
@@ -192,8 +250,11 @@ DO UPDATE SET
   html_url = EXCLUDED.html_url, 
   clone_url = EXCLUDED.clone_url, 
   clone_ssh_url = EXCLUDED.clone_ssh_url, 
-  is_fork = EXCLUDED.is_fork
-RETURNING id, remote_id, name, username, description, html_url, clone_url, clone_ssh_url, is_fork, fork_url
+  is_fork = EXCLUDED.is_fork,
+  source = EXCLUDED.source,
+  synced_at = EXCLUDED.synced_at,
+  is_archived = EXCLUDED.is_archived
+RETURNING id, remote_id, name, username, description, html_url, clone_url, clone_ssh_url, is_fork, fork_url, source, synced_at, is_archived
 `
 
 type RepoUpsertParams struct {
@@ -206,6 +267,9 @@ type RepoUpsertParams struct {
 	CloneSshUrl string
 	IsFork      bool
 	ForkUrl     string
+	Source      string
+	SyncedAt    int64
+	IsArchived  sql.NullBool
 }
 
 func (q *Queries) RepoUpsert(ctx context.Context, arg RepoUpsertParams) (Repository, error) {
@@ -219,6 +283,9 @@ func (q *Queries) RepoUpsert(ctx context.Context, arg RepoUpsertParams) (Reposit
 		arg.CloneSshUrl,
 		arg.IsFork,
 		arg.ForkUrl,
+		arg.Source,
+		arg.SyncedAt,
+		arg.IsArchived,
 	)
 	var i Repository
 	err := row.Scan(
@@ -232,43 +299,62 @@ func (q *Queries) RepoUpsert(ctx context.Context, arg RepoUpsertParams) (Reposit
 		&i.CloneSshUrl,
 		&i.IsFork,
 		&i.ForkUrl,
+		&i.Source,
+		&i.SyncedAt,
+		&i.IsArchived,
 	)
 	return i, err
 }
 
 const repoUpsertArtifact = `-- name: RepoUpsertArtifact :one
 INSERT INTO 
-  repository_artifact (repository_id, data_type, data)  
+  repository_artifact (repository_id, data_type, data, fetched_at, expires_at, content_hash)  
 VALUES 
-  (?, ?, ?)
+  (?, ?, ?, ?, ?, ?)
 ON CONFLICT (repository_id, data_type)
 DO UPDATE SET 
-  data = EXCLUDED.data  
+  data = EXCLUDED.data,
+  fetched_at = EXCLUDED.fetched_at,
+  expires_at = EXCLUDED.expires_at,
+  content_hash = EXCLUDED.content_hash
 RETURNING
-  id, data_type, data, repository_id
+  id, data_type, data, repository_id, fetched_at, expires_at, content_hash
 `
 
 type RepoUpsertArtifactParams struct {
 	RepositoryID int64
 	DataType     string
 	Data         []byte
+	FetchedAt    int64
+	ExpiresAt    int64
+	ContentHash  string
 }
 
 func (q *Queries) RepoUpsertArtifact(ctx context.Context, arg RepoUpsertArtifactParams) (RepositoryArtifact, error) {
-	row := q.db.QueryRowContext(ctx, repoUpsertArtifact, arg.RepositoryID, arg.DataType, arg.Data)
+	row := q.db.QueryRowContext(ctx, repoUpsertArtifact,
+		arg.RepositoryID,
+		arg.DataType,
+		arg.Data,
+		arg.FetchedAt,
+		arg.ExpiresAt,
+		arg.ContentHash,
+	)
 	var i RepositoryArtifact
 	err := row.Scan(
 		&i.ID,
 		&i.DataType,
 		&i.Data,
 		&i.RepositoryID,
+		&i.FetchedAt,
+		&i.ExpiresAt,
+		&i.ContentHash,
 	)
 	return i, err
 }
 
 const reposByNameLike = `-- name: ReposByNameLike :many
 SELECT 
-  id, remote_id, name, username, description, html_url, clone_url, clone_ssh_url, is_fork, fork_url 
+  id, remote_id, name, username, description, html_url, clone_url, clone_ssh_url, is_fork, fork_url, source, synced_at, is_archived 
 FROM  
   repository 
 WHERE 
@@ -295,6 +381,9 @@ func (q *Queries) ReposByNameLike(ctx context.Context, name string) ([]Repositor
 			&i.CloneSshUrl,
 			&i.IsFork,
 			&i.ForkUrl,
+			&i.Source,
+			&i.SyncedAt,
+			&i.IsArchived,
 		); err != nil {
 			return nil, err
 		}
@@ -311,7 +400,7 @@ func (q *Queries) ReposByNameLike(ctx context.Context, name string) ([]Repositor
 
 const reposByUsernameLike = `-- name: ReposByUsernameLike :many
 SELECT 
-  id, remote_id, name, username, description, html_url, clone_url, clone_ssh_url, is_fork, fork_url 
+  id, remote_id, name, username, description, html_url, clone_url, clone_ssh_url, is_fork, fork_url, source, synced_at, is_archived 
 FROM 
   repository 
 WHERE 
@@ -338,6 +427,9 @@ func (q *Queries) ReposByUsernameLike(ctx context.Context, username string) ([]R
 			&i.CloneSshUrl,
 			&i.IsFork,
 			&i.ForkUrl,
+			&i.Source,
+			&i.SyncedAt,
+			&i.IsArchived,
 		); err != nil {
 			return nil, err
 		}
@@ -354,7 +446,7 @@ func (q *Queries) ReposByUsernameLike(ctx context.Context, username string) ([]R
 
 const reposGetAll = `-- name: ReposGetAll :many
 SELECT 
-  id, remote_id, name, username, description, html_url, clone_url, clone_ssh_url, is_fork, fork_url 
+  id, remote_id, name, username, description, html_url, clone_url, clone_ssh_url, is_fork, fork_url, source, synced_at, is_archived 
 FROM  
   repository
 `
@@ -379,6 +471,64 @@ func (q *Queries) ReposGetAll(ctx context.Context) ([]Repository, error) {
 			&i.CloneSshUrl,
 			&i.IsFork,
 			&i.ForkUrl,
+			&i.Source,
+			&i.SyncedAt,
+			&i.IsArchived,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const reposWithStaleArtifact = `-- name: ReposWithStaleArtifact :many
+SELECT 
+  repository.id, repository.remote_id, repository.name, repository.username, repository.description, repository.html_url, repository.clone_url, repository.clone_ssh_url, repository.is_fork, repository.fork_url, repository.source, repository.synced_at, repository.is_archived 
+FROM 
+  repository 
+  LEFT JOIN repository_artifact 
+    ON repository_artifact.repository_id = repository.id 
+    AND repository_artifact.data_type = ? 
+WHERE 
+  repository_artifact.id IS NULL 
+  OR repository_artifact.expires_at <= ?
+`
+
+type ReposWithStaleArtifactParams struct {
+	DataType  string
+	ExpiresAt int64
+}
+
+func (q *Queries) ReposWithStaleArtifact(ctx context.Context, arg ReposWithStaleArtifactParams) ([]Repository, error) {
+	rows, err := q.db.QueryContext(ctx, reposWithStaleArtifact, arg.DataType, arg.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Repository
+	for rows.Next() {
+		var i Repository
+		if err := rows.Scan(
+			&i.ID,
+			&i.RemoteID,
+			&i.Name,
+			&i.Username,
+			&i.Description,
+			&i.HtmlUrl,
+			&i.CloneUrl,
+			&i.CloneSshUrl,
+			&i.IsFork,
+			&i.ForkUrl,
+			&i.Source,
+			&i.SyncedAt,
+			&i.IsArchived,
 		); err != nil {
 			return nil, err
 		}
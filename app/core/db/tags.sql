@@ -0,0 +1,54 @@
+-- name: TagCreate :exec
+INSERT INTO 
+  repository_tag (repository_id, tag) 
+VALUES 
+  (?, ?) 
+ON CONFLICT (repository_id, tag) 
+DO NOTHING;
+
+-- name: TagDelete :exec
+DELETE FROM 
+  repository_tag 
+WHERE 
+  repository_id = ? 
+  AND tag = ?;
+
+-- name: TagsByRepository :many
+SELECT 
+  tag 
+FROM  
+  repository_tag 
+WHERE 
+  repository_id = ? 
+ORDER BY 
+  tag;
+
+-- name: TagsGetAll :many
+SELECT 
+  repository_id, 
+  tag 
+FROM  
+  repository_tag 
+ORDER BY 
+  tag;
+
+-- name: NoteUpsert :exec
+INSERT INTO 
+  repository_note (repository_id, note) 
+VALUES 
+  (?, ?) 
+ON CONFLICT (repository_id) 
+DO UPDATE SET 
+  note = EXCLUDED.note;
+
+-- name: NoteDelete :exec
+DELETE FROM 
+  repository_note 
+WHERE 
+  repository_id = ?;
+
+-- name: NotesGetAll :many
+SELECT 
+  * 
+FROM  
+  repository_note;
@@ -0,0 +1,101 @@
+// Package git wraps the git command line for the repository operations used by
+// the non-interactive commands.
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Binary is the git executable used to run commands.
+var Binary = "git"
+
+// Run runs git with the arguments in the directory and returns the trimmed
+// stdout. If the command fails, the error includes the stderr output.
+func Run(ctx context.Context, dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, Binary, args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			return "", fmt.Errorf("git %s: %w", args[0], err)
+		}
+
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, msg)
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// Clone clones the repository at url into dir. The repository is cloned into a
+// temporary directory next to dir and moved into place once the clone is
+// complete, so an interrupted clone never leaves a partial repository at dir.
+func Clone(ctx context.Context, url, dir string) error {
+	_, err := os.Stat(dir)
+	if err == nil {
+		return fmt.Errorf("git clone: destination %s already exists", dir)
+	}
+
+	parent := filepath.Dir(dir)
+	err = os.MkdirAll(parent, 0o755)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.MkdirTemp(parent, "."+filepath.Base(dir)+".clone-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	// git creates the clone inside the temporary directory so the clone gets
+	// the same permissions as a plain git clone instead of the 0700 of tmp
+	clone := filepath.Join(tmp, filepath.Base(dir))
+
+	_, err = Run(ctx, "", "clone", "--quiet", url, clone)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(clone, dir)
+}
+
+// Fetch fetches all remotes of the repository in dir and prunes the remote
+// tracking branches that no longer exist.
+func Fetch(ctx context.Context, dir string) error {
+	_, err := Run(ctx, dir, "fetch", "--all", "--prune", "--quiet")
+	return err
+}
+
+// FastForward fast-forwards the checked out branch of the repository in dir to
+// its upstream without fetching. It returns true if HEAD was moved and fails if
+// the branch cannot be fast-forwarded.
+func FastForward(ctx context.Context, dir string) (bool, error) {
+	before, err := Run(ctx, dir, "rev-parse", "HEAD")
+	if err != nil {
+		return false, err
+	}
+
+	_, err = Run(ctx, dir, "merge", "--ff-only", "--quiet", "@{upstream}")
+	if err != nil {
+		return false, err
+	}
+
+	after, err := Run(ctx, dir, "rev-parse", "HEAD")
+	if err != nil {
+		return false, err
+	}
+
+	return before != after, nil
+}
@@ -0,0 +1,208 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+// tRepo creates a git repository with a single commit and returns its path.
+func tRepo(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	cmds := [][]string{
+		{"init", "--quiet", "--initial-branch=main"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "test"},
+		{"commit", "--quiet", "--allow-empty", "-m", "initial commit"},
+	}
+
+	for _, args := range cmds {
+		if _, err := Run(ctx, dir, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func Test_Clone(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	src := tRepo(t)
+	dst := filepath.Join(t.TempDir(), "nested", "clone")
+
+	err := Clone(ctx, src, dst)
+	is.NoErr(err)
+
+	_, err = os.Stat(filepath.Join(dst, ".git"))
+	is.NoErr(err) // clone should create the repository
+
+	plain := filepath.Join(t.TempDir(), "plain")
+	_, err = Run(ctx, "", "clone", "--quiet", src, plain)
+	is.NoErr(err)
+
+	want, err := os.Stat(plain)
+	is.NoErr(err)
+	got, err := os.Stat(dst)
+	is.NoErr(err)
+	is.Equal(got.Mode().Perm(), want.Mode().Perm()) // same permissions as a plain git clone
+
+	err = Clone(ctx, src, dst)
+	is.True(err != nil) // cloning into an existing repository fails
+
+	err = Clone(ctx, filepath.Join(t.TempDir(), "missing"), filepath.Join(filepath.Dir(dst), "failed"))
+	is.True(err != nil) // cloning a missing repository fails
+
+	entries, err := os.ReadDir(filepath.Dir(dst))
+	is.NoErr(err)
+	is.Equal(len(entries), 1) // failed clones are removed
+}
+
+func Test_GetStatus(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	src := tRepo(t)
+	dir := filepath.Join(t.TempDir(), "clone")
+	is.NoErr(Clone(ctx, src, dir))
+
+	run := func(args ...string) {
+		t.Helper()
+		_, err := Run(ctx, dir, args...)
+		is.NoErr(err)
+	}
+
+	run("config", "user.email", "test@example.com")
+	run("config", "user.name", "test")
+
+	s, err := GetStatus(ctx, dir)
+	is.NoErr(err)
+	is.Equal(s, Status{Branch: "main", Upstream: "origin/main"})
+	is.True(!s.IsDirty())
+	is.True(!s.HasUnpushed())
+
+	// one commit ahead, one modified file, one untracked file and one stash
+	is.NoErr(os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0o644))
+	run("add", "a.txt")
+	run("commit", "--quiet", "-m", "add a")
+
+	is.NoErr(os.WriteFile(filepath.Join(dir, "b.txt"), []byte("b"), 0o644))
+	run("add", "b.txt")
+	run("stash", "--quiet")
+
+	is.NoErr(os.WriteFile(filepath.Join(dir, "a.txt"), []byte("changed"), 0o644))
+	is.NoErr(os.WriteFile(filepath.Join(dir, "c.txt"), []byte("c"), 0o644))
+
+	s, err = GetStatus(ctx, dir)
+	is.NoErr(err)
+	is.Equal(s, Status{
+		Branch:    "main",
+		Upstream:  "origin/main",
+		Ahead:     1,
+		Dirty:     1,
+		Untracked: 1,
+		Stashes:   1,
+	})
+	is.True(s.IsDirty())
+	is.True(s.HasUnpushed())
+}
+
+func Test_parseStatus(t *testing.T) {
+	is := is.New(t)
+
+	out := `# branch.oid 1234
+# branch.head feature
+# branch.upstream origin/feature
+# branch.ab +0 -3
+1 .M N... 100644 100644 100644 abc abc file.go
+2 R. N... 100644 100644 100644 abc abc R100 new.go	old.go
+u UU N... 100644 100644 100644 100644 abc abc abc conflict.go
+? untracked.go
+! ignored.go`
+
+	s, err := parseStatus(out)
+	is.NoErr(err)
+	is.Equal(s, Status{
+		Branch:    "feature",
+		Upstream:  "origin/feature",
+		Behind:    3,
+		Dirty:     3,
+		Untracked: 1,
+	})
+
+	s, err = parseStatus("# branch.oid 1234\n# branch.head (detached)")
+	is.NoErr(err)
+	is.True(!s.HasUnpushed()) // detached HEAD has no upstream to push to
+}
+
+func Test_FetchFastForward(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	src := tRepo(t)
+	dir := filepath.Join(t.TempDir(), "clone")
+	is.NoErr(Clone(ctx, src, dir))
+
+	updated, err := FastForward(ctx, dir)
+	is.NoErr(err)
+	is.True(!updated) // want up to date
+
+	_, err = Run(ctx, src, "commit", "--quiet", "--allow-empty", "-m", "second commit")
+	is.NoErr(err)
+
+	is.NoErr(Fetch(ctx, dir))
+
+	s, err := GetStatus(ctx, dir)
+	is.NoErr(err)
+	is.Equal(s.Behind, 1) // want fetch to update the upstream
+
+	updated, err = FastForward(ctx, dir)
+	is.NoErr(err)
+	is.True(updated) // want HEAD moved to upstream
+
+	// diverged branches cannot be fast-forwarded
+	_, err = Run(ctx, src, "commit", "--quiet", "--allow-empty", "-m", "third commit")
+	is.NoErr(err)
+	_, err = Run(ctx, dir, "-c", "user.email=test@example.com", "-c", "user.name=test", "commit", "--quiet", "--allow-empty", "-m", "local commit")
+	is.NoErr(err)
+	is.NoErr(Fetch(ctx, dir))
+
+	_, err = FastForward(ctx, dir)
+	is.True(err != nil) // want error for diverged branch
+}
+
+func Test_ParseRemote(t *testing.T) {
+	tests := []struct {
+		remote   string
+		wantHost string
+		wantPath string
+		wantOk   bool
+	}{
+		{remote: "https://github.com/hay-kot/repomgr.git", wantHost: "github.com", wantPath: "hay-kot/repomgr", wantOk: true},
+		{remote: "https://github.com/Hay-Kot/RepoMgr", wantHost: "github.com", wantPath: "hay-kot/repomgr", wantOk: true},
+		{remote: "git@github.com:hay-kot/repomgr.git", wantHost: "github.com", wantPath: "hay-kot/repomgr", wantOk: true},
+		{remote: "ssh://git@github.com/hay-kot/repomgr.git", wantHost: "github.com", wantPath: "hay-kot/repomgr", wantOk: true},
+		{remote: "/local/path/repo", wantOk: false},
+		{remote: "https://github.com/hay-kot", wantOk: false},
+		{remote: "", wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.remote, func(t *testing.T) {
+			is := is.New(t)
+
+			host, path, ok := ParseRemote(tt.remote)
+			is.Equal(ok, tt.wantOk)
+			is.Equal(host, tt.wantHost)
+			is.Equal(path, tt.wantPath)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package git
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// RemoteURL returns the url of the remote of the repository in dir.
+func RemoteURL(ctx context.Context, dir, remote string) (string, error) {
+	return Run(ctx, dir, "remote", "get-url", remote)
+}
+
+// ParseRemote returns the host and the "owner/name" path of a remote url in
+// the https, ssh or scp-like ("git@host:owner/name.git") format. The results
+// are lowercased so urls in different formats for the same repository are
+// equal.
+func ParseRemote(remote string) (host string, path string, ok bool) {
+	remote = strings.TrimSpace(remote)
+
+	if strings.Contains(remote, "://") {
+		u, err := url.Parse(remote)
+		if err != nil {
+			return "", "", false
+		}
+
+		host, path = u.Hostname(), u.Path
+	} else {
+		// scp-like syntax, e.g. git@github.com:owner/name.git
+		userHost, p, found := strings.Cut(remote, ":")
+		if !found {
+			return "", "", false
+		}
+
+		_, host, found = strings.Cut(userHost, "@")
+		if !found {
+			host = userHost
+		}
+
+		path = p
+	}
+
+	path = strings.Trim(path, "/")
+	path = strings.TrimSuffix(path, ".git")
+
+	if host == "" || strings.Count(path, "/") != 1 {
+		return "", "", false
+	}
+
+	return strings.ToLower(host), strings.ToLower(path), true
+}
@@ -0,0 +1,99 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Status is the working tree status of a repository.
+type Status struct {
+	// Branch is the checked out branch, or "(detached)" for a detached HEAD.
+	Branch string `json:"branch"`
+	// Upstream is the upstream branch, empty if the branch does not track one.
+	Upstream string `json:"upstream"`
+	Ahead    int    `json:"ahead"`
+	Behind   int    `json:"behind"`
+	// Dirty is the number of staged, unstaged and unmerged files.
+	Dirty     int `json:"dirty"`
+	Untracked int `json:"untracked"`
+	Stashes   int `json:"stashes"`
+}
+
+// IsDirty returns true if the working tree has changes or untracked files.
+func (s Status) IsDirty() bool {
+	return s.Dirty > 0 || s.Untracked > 0
+}
+
+// HasUnpushed returns true if the repository has work that only exists
+// locally: commits ahead of the upstream, stashes, or a branch without an
+// upstream.
+func (s Status) HasUnpushed() bool {
+	return s.Ahead > 0 || s.Stashes > 0 || (s.Upstream == "" && s.Branch != "(detached)")
+}
+
+// GetStatus returns the status of the repository in dir.
+func GetStatus(ctx context.Context, dir string) (Status, error) {
+	out, err := Run(ctx, dir, "status", "--porcelain=v2", "--branch")
+	if err != nil {
+		return Status{}, err
+	}
+
+	s, err := parseStatus(out)
+	if err != nil {
+		return Status{}, err
+	}
+
+	stashes, err := Run(ctx, dir, "stash", "list")
+	if err != nil {
+		return Status{}, err
+	}
+
+	if stashes != "" {
+		s.Stashes = strings.Count(stashes, "\n") + 1
+	}
+
+	return s, nil
+}
+
+// parseStatus parses the output of `git status --porcelain=v2 --branch`.
+func parseStatus(out string) (Status, error) {
+	s := Status{}
+
+	for _, line := range strings.Split(out, "\n") {
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "# branch.head "):
+			s.Branch = strings.TrimPrefix(line, "# branch.head ")
+		case strings.HasPrefix(line, "# branch.upstream "):
+			s.Upstream = strings.TrimPrefix(line, "# branch.upstream ")
+		case strings.HasPrefix(line, "# branch.ab "):
+			// # branch.ab +<ahead> -<behind>
+			fields := strings.Fields(strings.TrimPrefix(line, "# branch.ab "))
+			if len(fields) != 2 {
+				return Status{}, fmt.Errorf("unexpected status line: %s", line)
+			}
+
+			ahead, err := strconv.Atoi(strings.TrimPrefix(fields[0], "+"))
+			if err != nil {
+				return Status{}, fmt.Errorf("unexpected status line: %s", line)
+			}
+
+			behind, err := strconv.Atoi(strings.TrimPrefix(fields[1], "-"))
+			if err != nil {
+				return Status{}, fmt.Errorf("unexpected status line: %s", line)
+			}
+
+			s.Ahead, s.Behind = ahead, behind
+		case strings.HasPrefix(line, "# "):
+			// other headers, e.g. branch.oid
+		case strings.HasPrefix(line, "? "):
+			s.Untracked++
+		case strings.HasPrefix(line, "1 "), strings.HasPrefix(line, "2 "), strings.HasPrefix(line, "u "):
+			s.Dirty++
+		}
+	}
+
+	return s, nil
+}
@@ -3,6 +3,8 @@ package repofs
 import (
 	"fmt"
 	"path/filepath"
+	"slices"
+	"strings"
 )
 
 type CloneDirectories struct {
@@ -52,3 +54,33 @@ func (c CloneDirectories) Validate() error {
 
 	return nil
 }
+
+// Roots returns the unique top level directories that repositories are cloned
+// into, e.g. "/path/to/repos" for "/path/to/repos/{{ .Repo.Name }}". The
+// default directory is always first.
+func (c CloneDirectories) Roots() []string {
+	dirs := make([]string, 0, len(c.Matchers)+1)
+	dirs = append(dirs, c.Default)
+	for _, matcher := range c.Matchers {
+		dirs = append(dirs, matcher.Directory)
+	}
+
+	roots := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		prefix := strings.Split(dir, "{{")[0]
+
+		var root string
+		if strings.HasSuffix(prefix, "/") {
+			root = filepath.Clean(prefix)
+		} else {
+			// the template is part of the last path element, e.g. "repo-{{ .Repo.Name }}"
+			root = filepath.Dir(prefix)
+		}
+
+		if !slices.Contains(roots, root) {
+			roots = append(roots, root)
+		}
+	}
+
+	return roots
+}
@@ -87,3 +87,17 @@ func Test_Matcher_IsMatch(t *testing.T) {
 		})
 	}
 }
+
+func Test_CloneDirectories_Roots(t *testing.T) {
+	dirs := CloneDirectories{
+		Default: "/src/{{ .Repo.Owner }}/{{ .Repo.Name }}",
+		Matchers: []Matcher{
+			{Match: "acme/*", Directory: "/work/acme/{{ .Repo.Name }}"},
+			{Match: "acme-labs/*", Directory: "/work/acme/labs-{{ .Repo.Name }}"},
+			{Match: "hay-kot/*", Directory: "/src/{{ .Repo.Name }}"},
+		},
+	}
+
+	is := is.New(t)
+	is.Equal(dirs.Roots(), []string{"/src", "/work/acme"})
+}
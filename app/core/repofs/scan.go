@@ -0,0 +1,63 @@
+package repofs
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FindGitRepositories walks root and returns the directories that contain a
+// ".git" entry, up to maxDepth levels below root. Repositories are not
+// descended into and hidden directories are skipped. A root that does not
+// exist returns no repositories.
+func FindGitRepositories(root string, maxDepth int) ([]string, error) {
+	root = filepath.Clean(root)
+	found := []string{}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root && errors.Is(err, fs.ErrNotExist) {
+				return filepath.SkipAll
+			}
+
+			// unreadable directories are skipped instead of failing the scan
+			if d != nil && d.IsDir() && path != root {
+				return filepath.SkipDir
+			}
+
+			return err
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		if path != root && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+
+		_, err = os.Stat(filepath.Join(path, ".git"))
+		if err == nil {
+			found = append(found, path)
+			return filepath.SkipDir
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		if rel != "." && strings.Count(rel, string(filepath.Separator))+1 >= maxDepth {
+			return filepath.SkipDir
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return found, nil
+}
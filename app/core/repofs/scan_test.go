@@ -0,0 +1,40 @@
+package repofs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func Test_FindGitRepositories(t *testing.T) {
+	is := is.New(t)
+	root := t.TempDir()
+
+	dirs := []string{
+		"acme/api/.git",
+		"acme/api/vendor/nested/.git", // inside a repository, not scanned
+		"acme/web/.git",
+		"flat/.git",
+		".hidden/repo/.git",
+		"too/deep/for/scan/.git",
+		"empty/dir",
+	}
+
+	for _, dir := range dirs {
+		is.NoErr(os.MkdirAll(filepath.Join(root, dir), 0o755))
+	}
+
+	got, err := FindGitRepositories(root, 3)
+	is.NoErr(err)
+	is.Equal(got, []string{
+		filepath.Join(root, "acme/api"),
+		filepath.Join(root, "acme/web"),
+		filepath.Join(root, "flat"),
+	})
+
+	got, err = FindGitRepositories(filepath.Join(root, "missing"), 3)
+	is.NoErr(err)
+	is.Equal(len(got), 0) // want no error for a missing root
+}
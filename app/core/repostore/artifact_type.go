@@ -1,5 +1,10 @@
 package repostore
 
+import (
+	"fmt"
+	"time"
+)
+
 type ArtifactType string
 
 func (a ArtifactType) String() string {
@@ -7,5 +12,54 @@ func (a ArtifactType) String() string {
 }
 
 const (
-	ArtifactTypeReadme ArtifactType = "repo.readme"
+	ArtifactTypeReadme        ArtifactType = "repo.readme"
+	ArtifactTypeLanguages     ArtifactType = "repo.languages"
+	ArtifactTypeLatestRelease ArtifactType = "repo.latest_release"
+	ArtifactTypeCodeOwners    ArtifactType = "repo.codeowners"
+	ArtifactTypeLicense       ArtifactType = "repo.license"
 )
+
+// ArtifactTypes returns all the supported artifact types.
+func ArtifactTypes() []ArtifactType {
+	return []ArtifactType{
+		ArtifactTypeReadme,
+		ArtifactTypeLanguages,
+		ArtifactTypeLatestRelease,
+		ArtifactTypeCodeOwners,
+		ArtifactTypeLicense,
+	}
+}
+
+func (a ArtifactType) IsValid() bool {
+	switch a {
+	case ArtifactTypeReadme,
+		ArtifactTypeLanguages,
+		ArtifactTypeLatestRelease,
+		ArtifactTypeCodeOwners,
+		ArtifactTypeLicense:
+		return true
+	default:
+		return false
+	}
+}
+
+func (a ArtifactType) Validate() error {
+	if !a.IsValid() {
+		return fmt.Errorf("invalid artifact type '%s'", a)
+	}
+
+	return nil
+}
+
+// TTL returns the duration an artifact of this type is considered fresh after
+// it has been fetched.
+func (a ArtifactType) TTL() time.Duration {
+	switch a {
+	case ArtifactTypeLatestRelease:
+		return 6 * time.Hour
+	case ArtifactTypeLanguages, ArtifactTypeLicense:
+		return 7 * 24 * time.Hour
+	default:
+		return 24 * time.Hour
+	}
+}
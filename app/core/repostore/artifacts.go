@@ -0,0 +1,167 @@
+package repostore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hay-kot/repomgr/app/core/db"
+	"github.com/hay-kot/repomgr/app/repos"
+)
+
+var ErrNoArtifactFound = errors.New("no artifact found")
+
+// Artifact is a piece of enrichment data for a repository that is fetched
+// from the remote source and cached in the database.
+type Artifact struct {
+	RepositoryID int
+	Type         ArtifactType
+	Data         []byte
+	FetchedAt    time.Time
+	ExpiresAt    time.Time
+	ContentHash  string
+}
+
+// IsStale returns true if the artifact has expired as of the given time.
+func (a Artifact) IsStale(now time.Time) bool {
+	return !now.Before(a.ExpiresAt)
+}
+
+// HashContent returns the hex encoded sha256 hash of the data.
+func HashContent(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// GetArtifact returns the cached artifact of the given type for the repository.
+// If no artifact has been stored ErrNoArtifactFound is returned.
+func (s *RepoStore) GetArtifact(ctx context.Context, repoID int, t ArtifactType) (Artifact, error) {
+	v, err := s.db.RepoArtifactByType(ctx, db.RepoArtifactByTypeParams{
+		RepositoryID: int64(repoID),
+		DataType:     t.String(),
+	})
+	if err != nil {
+		return Artifact{}, err
+	}
+
+	if len(v) == 0 {
+		return Artifact{}, ErrNoArtifactFound
+	}
+
+	return mapArtifact(v[0]), nil
+}
+
+// GetArtifacts returns all the cached artifacts for the repository.
+func (s *RepoStore) GetArtifacts(ctx context.Context, repoID int) ([]Artifact, error) {
+	v, err := s.db.RepoArtifacts(ctx, int64(repoID))
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Artifact, len(v))
+	for i, item := range v {
+		results[i] = mapArtifact(item)
+	}
+
+	return results, nil
+}
+
+// SetArtifact stores the artifact data for the repository, stamping it with the
+// current time and the TTL of the artifact type. It returns true if the content
+// changed compared to the previously stored artifact.
+func (s *RepoStore) SetArtifact(ctx context.Context, repoID int, t ArtifactType, data []byte) (changed bool, err error) {
+	hash := HashContent(data)
+
+	prev, err := s.GetArtifact(ctx, repoID, t)
+	switch {
+	case err == nil:
+		changed = prev.ContentHash != hash
+	case errors.Is(err, ErrNoArtifactFound):
+		changed = true
+	default:
+		return false, err
+	}
+
+	now := time.Now()
+	_, err = s.db.RepoUpsertArtifact(ctx, db.RepoUpsertArtifactParams{
+		RepositoryID: int64(repoID),
+		DataType:     t.String(),
+		Data:         data,
+		FetchedAt:    now.Unix(),
+		ExpiresAt:    now.Add(t.TTL()).Unix(),
+		ContentHash:  hash,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return changed, nil
+}
+
+// StaleRepositories returns the repositories where the artifact of the given
+// type is missing or has expired as of the given time.
+func (s *RepoStore) StaleRepositories(ctx context.Context, t ArtifactType, now time.Time) ([]repos.Repository, error) {
+	v, err := s.db.ReposWithStaleArtifact(ctx, db.ReposWithStaleArtifactParams{
+		DataType:  t.String(),
+		ExpiresAt: now.Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.hydrate(ctx, v)
+}
+
+func mapArtifact(v db.RepositoryArtifact) Artifact {
+	return Artifact{
+		RepositoryID: int(v.RepositoryID),
+		Type:         ArtifactType(v.DataType),
+		Data:         v.Data,
+		FetchedAt:    time.Unix(v.FetchedAt, 0),
+		ExpiresAt:    time.Unix(v.ExpiresAt, 0),
+		ContentHash:  v.ContentHash,
+	}
+}
+
+// GetLanguages returns the languages of the repository from the cached languages
+// artifact, ordered by the number of bytes written in each language. If the
+// artifact has not been fetched, an empty list is returned.
+func (s *RepoStore) GetLanguages(ctx context.Context, repoID int) ([]string, error) {
+	a, err := s.GetArtifact(ctx, repoID, ArtifactTypeLanguages)
+	if err != nil {
+		if errors.Is(err, ErrNoArtifactFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	if len(a.Data) == 0 {
+		return nil, nil
+	}
+
+	var languages map[string]int
+	err = json.Unmarshal(a.Data, &languages)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]string, 0, len(languages))
+	for lang := range languages {
+		results = append(results, lang)
+	}
+
+	slices.SortFunc(results, func(a, b string) int {
+		if languages[a] != languages[b] {
+			return languages[b] - languages[a]
+		}
+		return strings.Compare(a, b)
+	})
+
+	return results, nil
+}
@@ -0,0 +1,125 @@
+package repostore
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hay-kot/repomgr/app/repos"
+	"github.com/matryer/is"
+)
+
+func Test_RepositoryService_SetArtifact(t *testing.T) {
+	service := tRepoStore(t)
+	is := is.New(t)
+	ctx := context.Background()
+
+	err := service.UpsertOne(ctx, factory(1)[0])
+	is.NoErr(err)
+
+	all, err := service.GetAll(ctx)
+	is.NoErr(err)
+	repo := all[0]
+
+	_, err = service.GetArtifact(ctx, repo.ID, ArtifactTypeLicense)
+	is.True(errors.Is(err, ErrNoArtifactFound)) // no artifact should exist
+
+	changed, err := service.SetArtifact(ctx, repo.ID, ArtifactTypeLicense, []byte("MIT"))
+	is.NoErr(err)
+	is.True(changed) // first write is a change
+
+	changed, err = service.SetArtifact(ctx, repo.ID, ArtifactTypeLicense, []byte("MIT"))
+	is.NoErr(err)
+	is.True(!changed) // same content is not a change
+
+	got, err := service.GetArtifact(ctx, repo.ID, ArtifactTypeLicense)
+	is.NoErr(err)
+	is.Equal(string(got.Data), "MIT")
+	is.Equal(got.ContentHash, HashContent([]byte("MIT")))
+	is.Equal(got.ExpiresAt.Sub(got.FetchedAt), ArtifactTypeLicense.TTL())
+	is.True(!got.IsStale(time.Now()))                                   // freshly fetched
+	is.True(got.IsStale(time.Now().Add(ArtifactTypeLicense.TTL() + 1))) // stale after TTL
+}
+
+func Test_RepositoryService_StaleRepositories(t *testing.T) {
+	service := tRepoStore(t)
+	is := is.New(t)
+	ctx := context.Background()
+
+	err := service.UpsertMany(ctx, factory(3))
+	is.NoErr(err)
+
+	all, err := service.GetAll(ctx)
+	is.NoErr(err)
+
+	stale, err := service.StaleRepositories(ctx, ArtifactTypeReadme, time.Now())
+	is.NoErr(err)
+	is.Equal(len(stale), 3) // all repositories are missing the artifact
+
+	err = service.SetReadme(ctx, all[0].ID, []byte("readme"))
+	is.NoErr(err)
+
+	stale, err = service.StaleRepositories(ctx, ArtifactTypeReadme, time.Now())
+	is.NoErr(err)
+	is.Equal(len(stale), 2) // fresh artifacts are excluded
+
+	stale, err = service.StaleRepositories(ctx, ArtifactTypeReadme, time.Now().Add(ArtifactTypeReadme.TTL()+time.Second))
+	is.NoErr(err)
+	is.Equal(len(stale), 3) // expired artifacts are included
+}
+
+func Test_Refresher_Refresh(t *testing.T) {
+	service := tRepoStore(t)
+	is := is.New(t)
+	ctx := context.Background()
+
+	err := service.UpsertMany(ctx, factory(5))
+	is.NoErr(err)
+
+	var calls atomic.Int32
+	fetch := func(ctx context.Context, repo repos.Repository, t ArtifactType) ([]byte, error) {
+		calls.Add(1)
+		if t == ArtifactTypeLicense {
+			return nil, errors.New("fetch failed")
+		}
+
+		return []byte(repo.Name), nil
+	}
+
+	refresher := NewRefresher(service, fetch, 2, []ArtifactType{ArtifactTypeReadme, ArtifactTypeLicense})
+
+	n, err := refresher.Refresh(ctx)
+	is.True(err != nil)               // license fetch errors are reported
+	is.Equal(n, 5)                    // readmes are still refreshed
+	is.Equal(calls.Load(), int32(10)) // every artifact is fetched
+
+	n, _ = refresher.Refresh(ctx)
+	is.Equal(n, 0)                    // fresh readmes are not fetched again
+	is.Equal(calls.Load(), int32(15)) // failed licenses are retried
+}
+
+func Test_RepositoryService_GetLanguages(t *testing.T) {
+	service := tRepoStore(t)
+	is := is.New(t)
+	ctx := context.Background()
+
+	err := service.UpsertOne(ctx, factory(1)[0])
+	is.NoErr(err)
+
+	all, err := service.GetAll(ctx)
+	is.NoErr(err)
+	repo := all[0]
+
+	langs, err := service.GetLanguages(ctx, repo.ID)
+	is.NoErr(err)
+	is.Equal(len(langs), 0) // no artifact fetched
+
+	_, err = service.SetArtifact(ctx, repo.ID, ArtifactTypeLanguages, []byte(`{"Shell":10,"Go":2000,"Makefile":10}`))
+	is.NoErr(err)
+
+	langs, err = service.GetLanguages(ctx, repo.ID)
+	is.NoErr(err)
+	is.Equal(langs, []string{"Go", "Makefile", "Shell"}) // ordered by bytes, then name
+}
@@ -0,0 +1,118 @@
+package repostore
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hay-kot/repomgr/app/repos"
+)
+
+type ChangeKind string
+
+func (c ChangeKind) String() string {
+	return string(c)
+}
+
+const (
+	ChangeFirstSeen   ChangeKind = "first_seen"
+	ChangeRenamed     ChangeKind = "renamed"
+	ChangeTransferred ChangeKind = "transferred"
+	ChangeArchived    ChangeKind = "archived"
+	ChangeUnarchived  ChangeKind = "unarchived"
+	ChangeDescription ChangeKind = "description_changed"
+)
+
+// ChangeKinds returns all the kinds of changes recorded in the change log.
+func ChangeKinds() []ChangeKind {
+	return []ChangeKind{
+		ChangeFirstSeen,
+		ChangeRenamed,
+		ChangeTransferred,
+		ChangeArchived,
+		ChangeUnarchived,
+		ChangeDescription,
+	}
+}
+
+func (c ChangeKind) IsValid() bool {
+	return slices.Contains(ChangeKinds(), c)
+}
+
+// Validate returns an error listing the valid kinds if c is not a known kind.
+func (c ChangeKind) Validate() error {
+	if c.IsValid() {
+		return nil
+	}
+
+	valid := make([]string, 0, len(ChangeKinds()))
+	for _, k := range ChangeKinds() {
+		valid = append(valid, k.String())
+	}
+
+	return fmt.Errorf("invalid change kind '%s', valid kinds are: %s", c, strings.Join(valid, ", "))
+}
+
+// Change is an entry in the change log of a repository. Changes are recorded
+// when a repository is upserted during a sync.
+type Change struct {
+	RepositoryID int
+	DisplayName  string
+	Kind         ChangeKind
+	Old          string
+	New          string
+	CreatedAt    time.Time
+}
+
+// DiffRepository returns the changes between the stored repository and the
+// repository returned by the remote source.
+func DiffRepository(prev, next repos.Repository) []Change {
+	var changes []Change
+
+	if prev.Name != next.Name {
+		changes = append(changes, Change{Kind: ChangeRenamed, Old: prev.Name, New: next.Name})
+	}
+
+	if prev.Owner != next.Owner {
+		changes = append(changes, Change{Kind: ChangeTransferred, Old: prev.Owner, New: next.Owner})
+	}
+
+	if prev.IsArchived != next.IsArchived {
+		kind := ChangeArchived
+		if !next.IsArchived {
+			kind = ChangeUnarchived
+		}
+
+		changes = append(changes, Change{Kind: kind})
+	}
+
+	if prev.Description != next.Description {
+		changes = append(changes, Change{Kind: ChangeDescription, Old: prev.Description, New: next.Description})
+	}
+
+	return changes
+}
+
+// ChangesSince returns the changes recorded since the given time, newest first.
+func (s *RepoStore) ChangesSince(ctx context.Context, t time.Time) ([]Change, error) {
+	v, err := s.db.ChangesSince(ctx, t.Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Change, len(v))
+	for i, item := range v {
+		results[i] = Change{
+			RepositoryID: int(item.RepositoryID),
+			DisplayName:  item.DisplayName,
+			Kind:         ChangeKind(item.Kind),
+			Old:          item.OldValue,
+			New:          item.NewValue,
+			CreatedAt:    time.Unix(item.CreatedAt, 0),
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,150 @@
+package repostore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hay-kot/repomgr/app/repos"
+	"github.com/matryer/is"
+)
+
+func Test_DiffRepository(t *testing.T) {
+	prev := factory(1)[0]
+
+	type tcase struct {
+		name   string
+		modify func(next *repos.Repository)
+		want   []ChangeKind
+	}
+
+	cases := []tcase{
+		{
+			name:   "no changes",
+			modify: func(next *repos.Repository) {},
+			want:   nil,
+		},
+		{
+			name:   "renamed",
+			modify: func(next *repos.Repository) { next.Name = "renamed" },
+			want:   []ChangeKind{ChangeRenamed},
+		},
+		{
+			name: "transferred and archived",
+			modify: func(next *repos.Repository) {
+				next.Owner = "new-owner"
+				next.IsArchived = true
+			},
+			want: []ChangeKind{ChangeTransferred, ChangeArchived},
+		},
+		{
+			name:   "description changed",
+			modify: func(next *repos.Repository) { next.Description = "new description" },
+			want:   []ChangeKind{ChangeDescription},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			next := prev
+			tc.modify(&next)
+
+			var got []ChangeKind
+			for _, c := range DiffRepository(prev, next) {
+				got = append(got, c.Kind)
+			}
+
+			is.Equal(got, tc.want)
+		})
+	}
+}
+
+func Test_RepositoryService_ChangesSince(t *testing.T) {
+	service := tRepoStore(t)
+	is := is.New(t)
+	ctx := context.Background()
+
+	start := time.Now().Add(-time.Second)
+
+	item := factory(1)[0]
+	is.NoErr(service.UpsertOne(ctx, item))
+
+	changes, err := service.ChangesSince(ctx, start)
+	is.NoErr(err)
+	is.Equal(len(changes), 1)
+	is.Equal(changes[0].Kind, ChangeFirstSeen)
+
+	// re-syncing without changes should not record anything
+	is.NoErr(service.UpsertOne(ctx, item))
+
+	changes, err = service.ChangesSince(ctx, start)
+	is.NoErr(err)
+	is.Equal(len(changes), 1)
+
+	oldName := item.Name
+	item.Name = "renamed"
+	is.NoErr(service.UpsertOne(ctx, item))
+
+	changes, err = service.ChangesSince(ctx, start)
+	is.NoErr(err)
+	is.Equal(len(changes), 2)
+	is.Equal(changes[0].Kind, ChangeRenamed) // newest first
+	is.Equal(changes[0].Old, oldName)
+	is.Equal(changes[0].New, "renamed")
+	is.Equal(changes[0].DisplayName, item.DisplayName())
+
+	changes, err = service.ChangesSince(ctx, time.Now().Add(time.Hour))
+	is.NoErr(err)
+	is.Equal(len(changes), 0)
+}
+
+func Test_RepositoryService_UpsertMany_UnknownArchived(t *testing.T) {
+	service := tRepoStore(t)
+	is := is.New(t)
+	ctx := context.Background()
+
+	start := time.Now().Add(-time.Second)
+
+	item := factory(1)[0]
+	is.NoErr(service.UpsertOne(ctx, item))
+
+	// repositories cached before the archived state was synced
+	_, err := service.sql.ExecContext(ctx, "UPDATE repository SET is_archived = NULL")
+	is.NoErr(err)
+
+	item.IsArchived = true
+
+	diff, err := service.DiffSync(ctx, []repos.Repository{item}, nil)
+	is.NoErr(err)
+	is.True(diff.IsEmpty())
+
+	is.NoErr(service.UpsertOne(ctx, item))
+
+	changes, err := service.ChangesSince(ctx, start)
+	is.NoErr(err)
+	is.Equal(len(changes), 1)
+	is.Equal(changes[0].Kind, ChangeFirstSeen)
+
+	// once the archived state is known changes are recorded again
+	item.IsArchived = false
+	is.NoErr(service.UpsertOne(ctx, item))
+
+	changes, err = service.ChangesSince(ctx, start)
+	is.NoErr(err)
+	is.Equal(len(changes), 2)
+	is.Equal(changes[0].Kind, ChangeUnarchived)
+}
+
+func Test_ChangeKind_Validate(t *testing.T) {
+	is := is.New(t)
+
+	for _, kind := range ChangeKinds() {
+		is.NoErr(kind.Validate())
+	}
+
+	err := ChangeKind("renmaed").Validate()
+	is.True(err != nil)
+	is.Equal(err.Error(), "invalid change kind 'renmaed', valid kinds are: first_seen, renamed, transferred, archived, unarchived, description_changed")
+}
@@ -0,0 +1,151 @@
+package repostore
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hay-kot/repomgr/app/repos"
+)
+
+// FieldChange is a change of a stored field of a repository that is not
+// recorded in the change log, e.g. the clone URL.
+type FieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// RepositoryDiff is a cached repository that differs from the repository
+// returned by the remote source.
+type RepositoryDiff struct {
+	Prev    repos.Repository
+	Next    repos.Repository
+	Changes []Change
+	Fields  []FieldChange
+}
+
+// SyncDiff is the effect saving the fetched repositories would have on the
+// cache.
+type SyncDiff struct {
+	// Added are the fetched repositories that are not cached.
+	Added []repos.Repository
+	// Missing are the cached repositories that were not fetched. The sync keeps
+	// them in the cache until they are pruned, see GetSyncedBefore.
+	Missing []repos.Repository
+	// Changed are the cached repositories that differ from the fetched ones.
+	Changed []RepositoryDiff
+}
+
+// IsEmpty returns true if the sync would not change the cache. Missing
+// repositories are kept by the sync and do not change the cache.
+func (d SyncDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Changed) == 0
+}
+
+// DiffSync compares the cached repositories to the repositories fetched from
+// the sources. Cached repositories that were not fetched are reported as
+// missing unless their source is listed in incomplete, e.g. because the source
+// failed to sync. Every list is sorted by the display name.
+func DiffSync(cached, fetched []repos.Repository, incomplete []string) SyncDiff {
+	var diff SyncDiff
+
+	byRemoteID := make(map[string]repos.Repository, len(cached))
+	for _, repo := range cached {
+		byRemoteID[repo.RemoteID] = repo
+	}
+
+	seen := make(map[string]bool, len(fetched))
+	for _, next := range fetched {
+		if seen[next.RemoteID] {
+			// the repository is returned by multiple sources
+			continue
+		}
+		seen[next.RemoteID] = true
+
+		prev, ok := byRemoteID[next.RemoteID]
+		if !ok {
+			diff.Added = append(diff.Added, next)
+			continue
+		}
+
+		changes := DiffRepository(prev, next)
+		fields := diffFields(prev, next)
+		if len(changes) > 0 || len(fields) > 0 {
+			diff.Changed = append(diff.Changed, RepositoryDiff{
+				Prev:    prev,
+				Next:    next,
+				Changes: changes,
+				Fields:  fields,
+			})
+		}
+	}
+
+	for _, repo := range cached {
+		if !seen[repo.RemoteID] && !slices.Contains(incomplete, repo.Source) {
+			diff.Missing = append(diff.Missing, repo)
+		}
+	}
+
+	byName := func(a, b repos.Repository) int {
+		return strings.Compare(a.DisplayName(), b.DisplayName())
+	}
+
+	slices.SortFunc(diff.Added, byName)
+	slices.SortFunc(diff.Missing, byName)
+	slices.SortFunc(diff.Changed, func(a, b RepositoryDiff) int {
+		return byName(a.Next, b.Next)
+	})
+
+	return diff
+}
+
+// diffFields returns the changes of the stored fields that are not covered by
+// DiffRepository.
+func diffFields(prev, next repos.Repository) []FieldChange {
+	fields := []FieldChange{
+		{Field: "html_url", Old: prev.HTMLURL, New: next.HTMLURL},
+		{Field: "clone_url", Old: prev.CloneURL, New: next.CloneURL},
+		{Field: "clone_ssh_url", Old: prev.CloneSSHURL, New: next.CloneSSHURL},
+		{Field: "is_fork", Old: strconv.FormatBool(prev.IsFork), New: strconv.FormatBool(next.IsFork)},
+		{Field: "fork_url", Old: prev.ForkURL, New: next.ForkURL},
+	}
+
+	var changed []FieldChange
+	for _, f := range fields {
+		if f.Old != f.New {
+			changed = append(changed, f)
+		}
+	}
+
+	return changed
+}
+
+// DiffSync compares the cached repositories to the repositories fetched from
+// the sources, see DiffSync. Archive changes are not reported for cached
+// repositories whose archived state is unknown.
+func (s *RepoStore) DiffSync(ctx context.Context, fetched []repos.Repository, incomplete []string) (SyncDiff, error) {
+	rows, err := s.db.ReposGetAll(ctx)
+	if err != nil {
+		return SyncDiff{}, err
+	}
+
+	byRemoteID := make(map[string]repos.Repository, len(fetched))
+	for _, next := range fetched {
+		if _, ok := byRemoteID[next.RemoteID]; !ok {
+			byRemoteID[next.RemoteID] = next
+		}
+	}
+
+	cached := make([]repos.Repository, len(rows))
+	for i, row := range rows {
+		if next, ok := byRemoteID[row.RemoteID]; ok {
+			cached[i] = mapStored(row, next)
+		} else {
+			cached[i] = mapRepository(row)
+		}
+	}
+
+	return DiffSync(cached, fetched, incomplete), nil
+}
@@ -0,0 +1,51 @@
+package repostore
+
+import (
+	"testing"
+
+	"github.com/hay-kot/repomgr/app/repos"
+	"github.com/matryer/is"
+)
+
+func Test_DiffSync(t *testing.T) {
+	is := is.New(t)
+
+	cached := factory(4)
+	for i := range cached {
+		cached[i].Source = "github/hay-kot"
+	}
+	cached[3].Source = "github/acme"
+
+	var (
+		unchanged = cached[0]
+		renamed   = cached[1]
+		added     = factory(1)[0]
+	)
+
+	renamed.Name = "renamed"
+	renamed.CloneURL = "https://github.com/hay-kot/renamed.git"
+
+	// cached[2] is no longer returned by its source and cached[3] belongs to a
+	// source that failed to sync
+	fetched := []repos.Repository{unchanged, renamed, added, unchanged}
+
+	diff := DiffSync(cached, fetched, []string{"github/acme"})
+
+	is.Equal(len(diff.Added), 1)
+	is.Equal(diff.Added[0].RemoteID, added.RemoteID)
+
+	is.Equal(len(diff.Missing), 1)
+	is.Equal(diff.Missing[0].RemoteID, cached[2].RemoteID)
+
+	is.Equal(len(diff.Changed), 1)
+	is.Equal(diff.Changed[0].Next.RemoteID, renamed.RemoteID)
+	is.Equal(len(diff.Changed[0].Changes), 1)
+	is.Equal(diff.Changed[0].Changes[0].Kind, ChangeRenamed)
+	is.Equal(diff.Changed[0].Fields, []FieldChange{
+		{Field: "clone_url", Old: cached[1].CloneURL, New: renamed.CloneURL},
+	})
+
+	is.True(!diff.IsEmpty())
+	is.True(DiffSync(cached[:1], cached[:1], nil).IsEmpty())
+	is.True(DiffSync(cached, cached[:1], nil).IsEmpty()) // missing repositories are kept in the cache
+}
@@ -0,0 +1,229 @@
+package repostore
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/hay-kot/repomgr/app/core/db"
+)
+
+// Record is the portable representation of a repository and its local state
+// used to export and import the database.
+type Record struct {
+	RemoteID    string           `json:"remote_id"`
+	Name        string           `json:"name"`
+	Owner       string           `json:"owner"`
+	Description string           `json:"description"`
+	HTMLURL     string           `json:"html_url"`
+	CloneURL    string           `json:"clone_url"`
+	CloneSSHURL string           `json:"clone_ssh_url"`
+	IsFork      bool             `json:"is_fork"`
+	ForkURL     string           `json:"fork_url"`
+	IsArchived  *bool            `json:"is_archived"` // nil if unknown
+	Source      string           `json:"source"`
+	SyncedAt    time.Time        `json:"synced_at"`
+	Pinned      bool             `json:"pinned"`
+	Tags        []string         `json:"tags"`
+	Note        string           `json:"note"`
+	Artifacts   []RecordArtifact `json:"artifacts,omitempty"`
+}
+
+type RecordArtifact struct {
+	Type        ArtifactType `json:"type"`
+	Data        []byte       `json:"data"`
+	FetchedAt   time.Time    `json:"fetched_at"`
+	ExpiresAt   time.Time    `json:"expires_at"`
+	ContentHash string       `json:"content_hash"`
+}
+
+// Export returns every repository in the store along with its pins, tags, notes
+// and artifacts.
+func (s *RepoStore) Export(ctx context.Context) ([]Record, error) {
+	rows, err := s.db.ReposGetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := s.hydrate(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]Record, len(all))
+	for i, repo := range all {
+		artifacts, err := s.GetArtifacts(ctx, repo.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		recordArtifacts := make([]RecordArtifact, len(artifacts))
+		for j, a := range artifacts {
+			recordArtifacts[j] = RecordArtifact{
+				Type:        a.Type,
+				Data:        a.Data,
+				FetchedAt:   a.FetchedAt.UTC(),
+				ExpiresAt:   a.ExpiresAt.UTC(),
+				ContentHash: a.ContentHash,
+			}
+		}
+
+		var archived *bool
+		if rows[i].IsArchived.Valid {
+			archived = &rows[i].IsArchived.Bool
+		}
+
+		records[i] = Record{
+			RemoteID:    repo.RemoteID,
+			Name:        repo.Name,
+			Owner:       repo.Owner,
+			Description: repo.Description,
+			HTMLURL:     repo.HTMLURL,
+			CloneURL:    repo.CloneURL,
+			CloneSSHURL: repo.CloneSSHURL,
+			IsFork:      repo.IsFork,
+			ForkURL:     repo.ForkURL,
+			IsArchived:  archived,
+			Source:      repo.Source,
+			SyncedAt:    repo.SyncedAt.UTC(),
+			Pinned:      repo.Pinned,
+			Tags:        repo.Tags,
+			Note:        repo.Note,
+			Artifacts:   recordArtifacts,
+		}
+	}
+
+	return records, nil
+}
+
+// Import merges the records into the store in a single transaction. Repositories
+// are matched on their remote id and are only overwritten when the record was
+// synced after the cached repository. Pins and tags are merged with the
+// existing state, notes are only overwritten when the imported note is not
+// empty, and artifacts are only overwritten when the imported artifact is
+// newer.
+func (s *RepoStore) Import(ctx context.Context, records []Record) error {
+	tx, err := s.sql.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	q := s.db.WithTx(tx)
+
+	for _, record := range records {
+		row, err := importRepository(ctx, q, record)
+		if err != nil {
+			return err
+		}
+
+		if record.Pinned {
+			err = q.PinCreate(ctx, row.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, tag := range record.Tags {
+			tag, err := NormalizeTag(tag)
+			if err != nil {
+				return err
+			}
+
+			err = q.TagCreate(ctx, db.TagCreateParams{RepositoryID: row.ID, Tag: tag})
+			if err != nil {
+				return err
+			}
+		}
+
+		if record.Note != "" {
+			err = q.NoteUpsert(ctx, db.NoteUpsertParams{RepositoryID: row.ID, Note: record.Note})
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, a := range record.Artifacts {
+			if err := a.Type.Validate(); err != nil {
+				return err
+			}
+
+			existing, err := q.RepoArtifactByType(ctx, db.RepoArtifactByTypeParams{
+				RepositoryID: row.ID,
+				DataType:     a.Type.String(),
+			})
+			if err != nil {
+				return err
+			}
+
+			if len(existing) > 0 && existing[0].FetchedAt >= a.FetchedAt.Unix() {
+				continue
+			}
+
+			hash := a.ContentHash
+			if hash == "" {
+				hash = HashContent(a.Data)
+			}
+
+			_, err = q.RepoUpsertArtifact(ctx, db.RepoUpsertArtifactParams{
+				RepositoryID: row.ID,
+				DataType:     a.Type.String(),
+				Data:         a.Data,
+				FetchedAt:    a.FetchedAt.Unix(),
+				ExpiresAt:    a.ExpiresAt.Unix(),
+				ContentHash:  hash,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// importRepository upserts the repository of the record unless the cached
+// repository was synced at the same time or later, in which case the cached
+// repository is returned unchanged. Records without a sync time are older than
+// any cached repository.
+func importRepository(ctx context.Context, q *db.Queries, record Record) (db.Repository, error) {
+	var syncedAt int64
+	if !record.SyncedAt.IsZero() {
+		syncedAt = record.SyncedAt.Unix()
+	}
+
+	existing, err := q.RepoByRemoteID(ctx, record.RemoteID)
+	if err != nil {
+		return db.Repository{}, err
+	}
+
+	var archived sql.NullBool
+	if record.IsArchived != nil {
+		archived = sql.NullBool{Bool: *record.IsArchived, Valid: true}
+	}
+
+	if len(existing) > 0 {
+		if existing[0].SyncedAt >= syncedAt {
+			return existing[0], nil
+		}
+
+		if !archived.Valid {
+			archived = existing[0].IsArchived
+		}
+	}
+
+	return q.RepoUpsert(ctx, db.RepoUpsertParams{
+		RemoteID:    record.RemoteID,
+		Name:        record.Name,
+		Username:    record.Owner,
+		Description: record.Description,
+		HtmlUrl:     record.HTMLURL,
+		CloneUrl:    record.CloneURL,
+		CloneSshUrl: record.CloneSSHURL,
+		IsFork:      record.IsFork,
+		ForkUrl:     record.ForkURL,
+		Source:      record.Source,
+		SyncedAt:    syncedAt,
+		IsArchived:  archived,
+	})
+}
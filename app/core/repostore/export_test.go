@@ -0,0 +1,121 @@
+package repostore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func Test_RepositoryService_ExportImport(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	src := tRepoStore(t)
+	err := src.UpsertMany(ctx, factory(3))
+	is.NoErr(err)
+
+	all, err := src.GetAll(ctx)
+	is.NoErr(err)
+
+	target := all[0]
+	_, err = src.TogglePin(ctx, target.ID)
+	is.NoErr(err)
+	is.NoErr(src.AddTags(ctx, target.ID, "infra"))
+	is.NoErr(src.SetNote(ctx, target.ID, "runbook"))
+	is.NoErr(src.SetReadme(ctx, target.ID, []byte("# readme")))
+
+	records, err := src.Export(ctx)
+	is.NoErr(err)
+	is.Equal(len(records), 3)
+
+	// the destination already has the target with a different tag, which
+	// should be merged rather than replaced
+	dst := tRepoStore(t)
+	is.NoErr(dst.UpsertOne(ctx, target))
+
+	existing, err := dst.GetByDisplayName(ctx, target.DisplayName())
+	is.NoErr(err)
+	is.NoErr(dst.AddTags(ctx, existing.ID, "local"))
+
+	err = dst.Import(ctx, records)
+	is.NoErr(err)
+
+	// importing twice should not duplicate records
+	err = dst.Import(ctx, records)
+	is.NoErr(err)
+
+	imported, err := dst.GetAll(ctx)
+	is.NoErr(err)
+	is.Equal(len(imported), 3)
+
+	got, err := dst.GetByDisplayName(ctx, target.DisplayName())
+	is.NoErr(err)
+	is.Equal(got.ID, existing.ID) // merged by remote id
+	is.True(got.Pinned)
+	is.Equal(got.Tags, []string{"infra", "local"})
+	is.Equal(got.Note, "runbook")
+
+	readme, err := dst.GetReadme(ctx, got.ID)
+	is.NoErr(err)
+	is.Equal(string(readme), "# readme")
+}
+
+func Test_RepositoryService_Import_KeepsNewer(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+
+	store := tRepoStore(t)
+	item := factory(1)[0]
+	is.NoErr(store.UpsertOne(ctx, item))
+
+	byRemoteID := func(remoteID string) Record {
+		records, err := store.Export(ctx)
+		is.NoErr(err)
+
+		for _, r := range records {
+			if r.RemoteID == remoteID {
+				return r
+			}
+		}
+
+		t.Fatalf("record %s not found", remoteID)
+		return Record{}
+	}
+
+	cached := byRemoteID(item.RemoteID)
+	archived := true
+
+	// an older record does not overwrite the cached repository
+	older := cached
+	older.Name = "older"
+	older.IsArchived = &archived
+	older.SyncedAt = cached.SyncedAt.Add(-time.Hour)
+	is.NoErr(store.Import(ctx, []Record{older}))
+
+	got := byRemoteID(item.RemoteID)
+	is.Equal(got.Name, item.Name)
+	is.Equal(got.SyncedAt, cached.SyncedAt)
+	is.Equal(*got.IsArchived, false)
+
+	// a newer record overwrites it but keeps the known archived state
+	newer := cached
+	newer.Name = "newer"
+	newer.IsArchived = nil
+	newer.SyncedAt = cached.SyncedAt.Add(time.Hour)
+	is.NoErr(store.Import(ctx, []Record{newer}))
+
+	got = byRemoteID(item.RemoteID)
+	is.Equal(got.Name, "newer")
+	is.Equal(got.SyncedAt, newer.SyncedAt)
+	is.Equal(*got.IsArchived, false)
+
+	// a record without sync time or archived state is stored as unknown
+	unknown := Record{RemoteID: "unknown", Name: "unknown", Owner: "acme"}
+	is.NoErr(store.Import(ctx, []Record{unknown}))
+
+	got = byRemoteID(unknown.RemoteID)
+	is.Equal(got.SyncedAt.Unix(), int64(0))
+	is.Equal(got.IsArchived, nil)
+}
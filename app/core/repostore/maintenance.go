@@ -0,0 +1,167 @@
+package repostore
+
+import (
+	"context"
+	"time"
+
+	"github.com/hay-kot/repomgr/app/core/db"
+	"github.com/hay-kot/repomgr/app/repos"
+)
+
+// GetBySource returns the repositories synced from the given source.
+func (s *RepoStore) GetBySource(ctx context.Context, source string) ([]repos.Repository, error) {
+	v, err := s.db.ReposBySource(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.hydrate(ctx, v)
+}
+
+// GetSyncedBefore returns the repositories that have not been seen in a sync
+// since the given time.
+func (s *RepoStore) GetSyncedBefore(ctx context.Context, t time.Time) ([]repos.Repository, error) {
+	v, err := s.db.ReposSyncedBefore(ctx, t.Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	return s.hydrate(ctx, v)
+}
+
+// DeleteRepositories removes the repositories along with their artifacts, tags,
+// notes, pins and change log in a single transaction.
+func (s *RepoStore) DeleteRepositories(ctx context.Context, ids []int) error {
+	tx, err := s.sql.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	q := s.db.WithTx(tx)
+	for _, id := range ids {
+		err := q.RepoDelete(ctx, int64(id))
+		if err != nil {
+			return err
+		}
+	}
+
+	// foreign keys are not guaranteed to be enforced by the connection, so
+	// dependent rows are removed explicitly.
+	_, err = deleteOrphans(ctx, q)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// VerifyReport is the result of an integrity check of the database.
+type VerifyReport struct {
+	// Integrity contains the problems reported by sqlite, it is empty when the
+	// database passes the integrity check.
+	Integrity []string
+
+	OrphanedArtifacts int64
+	OrphanedTags      int64
+	OrphanedNotes     int64
+	OrphanedPins      int64
+	OrphanedChanges   int64
+}
+
+// Orphans returns the total number of rows that reference a repository that
+// no longer exists.
+func (r VerifyReport) Orphans() int64 {
+	return r.OrphanedArtifacts + r.OrphanedTags + r.OrphanedNotes + r.OrphanedPins + r.OrphanedChanges
+}
+
+// Ok returns true if the database passed the integrity check and has no orphans.
+func (r VerifyReport) Ok() bool {
+	return len(r.Integrity) == 0 && r.Orphans() == 0
+}
+
+// Verify runs the sqlite integrity check and counts the orphaned rows.
+func (s *RepoStore) Verify(ctx context.Context) (VerifyReport, error) {
+	report := VerifyReport{}
+
+	rows, err := s.sql.QueryContext(ctx, "PRAGMA integrity_check")
+	if err != nil {
+		return report, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return report, err
+		}
+
+		if line != "ok" {
+			report.Integrity = append(report.Integrity, line)
+		}
+	}
+
+	if err := rows.Close(); err != nil {
+		return report, err
+	}
+
+	counters := []struct {
+		dst *int64
+		fn  func(context.Context) (int64, error)
+	}{
+		{&report.OrphanedArtifacts, s.db.ArtifactsCountOrphaned},
+		{&report.OrphanedTags, s.db.TagsCountOrphaned},
+		{&report.OrphanedNotes, s.db.NotesCountOrphaned},
+		{&report.OrphanedPins, s.db.PinsCountOrphaned},
+		{&report.OrphanedChanges, s.db.ChangesCountOrphaned},
+	}
+
+	for _, c := range counters {
+		*c.dst, err = c.fn(ctx)
+		if err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// DeleteOrphans removes the rows that reference a repository that no longer
+// exists. It returns the number of rows removed.
+func (s *RepoStore) DeleteOrphans(ctx context.Context) (int64, error) {
+	return deleteOrphans(ctx, s.db)
+}
+
+func deleteOrphans(ctx context.Context, q *db.Queries) (int64, error) {
+	deleters := []func(context.Context) (int64, error){
+		q.ArtifactsDeleteOrphaned,
+		q.TagsDeleteOrphaned,
+		q.NotesDeleteOrphaned,
+		q.PinsDeleteOrphaned,
+		q.ChangesDeleteOrphaned,
+	}
+
+	var total int64
+	for _, fn := range deleters {
+		n, err := fn(ctx)
+		if err != nil {
+			return total, err
+		}
+
+		total += n
+	}
+
+	return total, nil
+}
+
+// Vacuum rebuilds the database file, reclaiming unused space. The write-ahead
+// log is checkpointed afterwards so the space is reclaimed on disk.
+func (s *RepoStore) Vacuum(ctx context.Context) error {
+	_, err := s.sql.ExecContext(ctx, "VACUUM")
+	if err != nil {
+		return err
+	}
+
+	_, err = s.sql.ExecContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)")
+	return err
+}
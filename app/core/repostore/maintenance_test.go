@@ -0,0 +1,100 @@
+package repostore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func Test_RepositoryService_DeleteRepositories(t *testing.T) {
+	service := tRepoStore(t)
+	is := is.New(t)
+	ctx := context.Background()
+
+	items := factory(3)
+	items[0].Source = "github/a"
+	items[1].Source = "github/a"
+	items[2].Source = "github/b"
+
+	err := service.UpsertMany(ctx, items)
+	is.NoErr(err)
+
+	bySource, err := service.GetBySource(ctx, "github/a")
+	is.NoErr(err)
+	is.Equal(len(bySource), 2)
+
+	for _, repo := range bySource {
+		is.NoErr(service.AddTags(ctx, repo.ID, "infra"))
+		is.NoErr(service.SetReadme(ctx, repo.ID, []byte("readme")))
+	}
+
+	err = service.DeleteRepositories(ctx, []int{bySource[0].ID, bySource[1].ID})
+	is.NoErr(err)
+
+	all, err := service.GetAll(ctx)
+	is.NoErr(err)
+	is.Equal(len(all), 1)
+	is.Equal(all[0].Source, "github/b")
+
+	report, err := service.Verify(ctx)
+	is.NoErr(err)
+	is.True(report.Ok()) // dependent rows are removed with the repository
+}
+
+func Test_RepositoryService_GetSyncedBefore(t *testing.T) {
+	service := tRepoStore(t)
+	is := is.New(t)
+	ctx := context.Background()
+
+	err := service.UpsertMany(ctx, factory(2))
+	is.NoErr(err)
+
+	stale, err := service.GetSyncedBefore(ctx, time.Now().Add(-time.Hour))
+	is.NoErr(err)
+	is.Equal(len(stale), 0) // freshly synced repositories are not stale
+
+	stale, err = service.GetSyncedBefore(ctx, time.Now().Add(time.Hour))
+	is.NoErr(err)
+	is.Equal(len(stale), 2)
+}
+
+func Test_RepositoryService_VerifyOrphans(t *testing.T) {
+	service := tRepoStore(t)
+	is := is.New(t)
+	ctx := context.Background()
+
+	err := service.UpsertOne(ctx, factory(1)[0])
+	is.NoErr(err)
+
+	all, err := service.GetAll(ctx)
+	is.NoErr(err)
+
+	id := all[0].ID
+	is.NoErr(service.AddTags(ctx, id, "a", "b"))
+	is.NoErr(service.SetNote(ctx, id, "note"))
+	_, err = service.TogglePin(ctx, id)
+	is.NoErr(err)
+
+	// delete the repository without removing the dependent rows
+	err = service.db.RepoDelete(ctx, int64(id))
+	is.NoErr(err)
+
+	report, err := service.Verify(ctx)
+	is.NoErr(err)
+	is.True(!report.Ok())
+	is.Equal(report.OrphanedTags, int64(2))
+	is.Equal(report.OrphanedNotes, int64(1))
+	is.Equal(report.OrphanedPins, int64(1))
+	is.Equal(report.OrphanedChanges, int64(1)) // first seen change
+	is.Equal(report.Orphans(), int64(5))
+
+	n, err := service.DeleteOrphans(ctx)
+	is.NoErr(err)
+	is.Equal(n, int64(5))
+
+	report, err = service.Verify(ctx)
+	is.NoErr(err)
+	is.True(report.Ok())
+}
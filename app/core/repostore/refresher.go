@@ -0,0 +1,109 @@
+package repostore
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/hay-kot/repomgr/app/repos"
+	"github.com/rs/zerolog/log"
+	"github.com/sourcegraph/conc/pool"
+)
+
+// ArtifactFetcher fetches the content of an artifact for a repository from the
+// remote source.
+type ArtifactFetcher func(ctx context.Context, repo repos.Repository, t ArtifactType) ([]byte, error)
+
+// Refresher keeps the cached artifacts of the repositories fresh by fetching
+// missing and expired artifacts from the remote source.
+type Refresher struct {
+	store       *RepoStore
+	fetch       ArtifactFetcher
+	concurrency int
+	types       []ArtifactType
+
+	// mu serializes writes to the store, sqlite only supports a single writer
+	mu sync.Mutex
+}
+
+func NewRefresher(store *RepoStore, fetch ArtifactFetcher, concurrency int, types []ArtifactType) *Refresher {
+	return &Refresher{
+		store:       store,
+		fetch:       fetch,
+		concurrency: concurrency,
+		types:       types,
+	}
+}
+
+// Refresh fetches every missing or expired artifact once. It returns the number
+// of artifacts that were refreshed. Failures for individual artifacts do not stop
+// the refresh, they are joined and returned once all artifacts are processed.
+func (r *Refresher) Refresh(ctx context.Context) (int, error) {
+	// the stale repositories of every type are collected before any fetch is
+	// queued so a failing query does not leave fetches running in the background
+	now := time.Now()
+	stale := make([][]repos.Repository, len(r.types))
+	for i, t := range r.types {
+		v, err := r.store.StaleRepositories(ctx, t, now)
+		if err != nil {
+			return 0, err
+		}
+
+		stale[i] = v
+	}
+
+	p := pool.NewWithResults[bool]().
+		WithMaxGoroutines(r.concurrency).
+		WithErrors().
+		WithContext(ctx)
+
+	for i, t := range r.types {
+		for _, repo := range stale[i] {
+			p.Go(func(ctx context.Context) (bool, error) {
+				data, err := r.fetch(ctx, repo, t)
+				if err != nil {
+					log.Warn().Err(err).
+						Str("repo", repo.DisplayName()).
+						Str("type", t.String()).
+						Msg("failed to fetch artifact")
+					return false, err
+				}
+
+				r.mu.Lock()
+				defer r.mu.Unlock()
+
+				_, err = r.store.SetArtifact(ctx, repo.ID, t, data)
+				if err != nil {
+					return false, err
+				}
+
+				return true, nil
+			})
+		}
+	}
+
+	results, err := p.Wait()
+	return len(results), err
+}
+
+// Run refreshes the artifacts every interval until the context is cancelled.
+func (r *Refresher) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := r.Refresh(ctx)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			log.Warn().Err(err).Msg("artifact refresh finished with errors")
+		}
+
+		log.Debug().Int("count", n).Msg("refreshed artifacts")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package repostore
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/hay-kot/repomgr/app/core/db"
+)
+
+// Relocation is a journal entry for a clone moved by the relocate command. The
+// entries of a single run share a batch so the run can be undone as a whole.
+type Relocation struct {
+	ID    int
+	Batch int64
+	From  string
+	To    string
+	// MovedAt is zero until the clone has been moved.
+	MovedAt time.Time
+}
+
+func mapRelocation(r db.CloneRelocation) Relocation {
+	rel := Relocation{
+		ID:    int(r.ID),
+		Batch: r.Batch,
+		From:  r.FromPath,
+		To:    r.ToPath,
+	}
+
+	if r.MovedAt.Valid {
+		rel.MovedAt = time.Unix(r.MovedAt.Int64, 0)
+	}
+
+	return rel
+}
+
+// JournalRelocations records the planned moves as a new batch before any clone
+// is moved, and returns the entries in the same order.
+func (s *RepoStore) JournalRelocations(ctx context.Context, moves []Relocation) ([]Relocation, error) {
+	tx, err := s.sql.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var (
+		q       = s.db.WithTx(tx)
+		now     = time.Now()
+		batch   = now.UnixNano()
+		results = make([]Relocation, len(moves))
+	)
+
+	for i, move := range moves {
+		v, err := q.RelocationCreate(ctx, db.RelocationCreateParams{
+			Batch:     batch,
+			FromPath:  move.From,
+			ToPath:    move.To,
+			CreatedAt: now.Unix(),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		results[i] = mapRelocation(v)
+	}
+
+	return results, tx.Commit()
+}
+
+// MarkRelocated marks the journal entry as moved.
+func (s *RepoStore) MarkRelocated(ctx context.Context, id int) error {
+	return s.db.RelocationMarkMoved(ctx, db.RelocationMarkMovedParams{
+		MovedAt: sql.NullInt64{Int64: time.Now().Unix(), Valid: true},
+		ID:      int64(id),
+	})
+}
+
+// MarkRelocationUndone marks the journal entry as moved back.
+func (s *RepoStore) MarkRelocationUndone(ctx context.Context, id int) error {
+	return s.db.RelocationMarkUndone(ctx, db.RelocationMarkUndoneParams{
+		UndoneAt: sql.NullInt64{Int64: time.Now().Unix(), Valid: true},
+		ID:       int64(id),
+	})
+}
+
+// UndoableRelocations returns the moved entries of the most recent batch that
+// has not been undone, newest first so they can be moved back in reverse order.
+func (s *RepoStore) UndoableRelocations(ctx context.Context) ([]Relocation, error) {
+	v, err := s.db.RelocationsUndoable(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Relocation, len(v))
+	for i, item := range v {
+		results[i] = mapRelocation(item)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,59 @@
+package repostore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func Test_RepositoryService_Relocations(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	store := tRepoStore(t)
+
+	journal := func(moves ...Relocation) []Relocation {
+		t.Helper()
+
+		v, err := store.JournalRelocations(ctx, moves)
+		is.NoErr(err)
+		return v
+	}
+
+	undoable := func() []Relocation {
+		t.Helper()
+
+		v, err := store.UndoableRelocations(ctx)
+		is.NoErr(err)
+		return v
+	}
+
+	first := journal(Relocation{From: "/a", To: "/b"})
+	is.NoErr(store.MarkRelocated(ctx, first[0].ID))
+
+	second := journal(
+		Relocation{From: "/c", To: "/d"},
+		Relocation{From: "/e", To: "/f"},
+		Relocation{From: "/g", To: "/h"}, // failed to move
+	)
+	is.Equal(len(second), 3)
+	is.True(second[0].Batch != first[0].Batch) // want a new batch for every run
+	is.True(second[0].MovedAt.IsZero())        // want entries to start unmoved
+
+	is.NoErr(store.MarkRelocated(ctx, second[0].ID))
+	is.NoErr(store.MarkRelocated(ctx, second[1].ID))
+
+	got := undoable()
+	is.Equal(len(got), 2) // want only the moved entries of the latest batch
+	is.Equal(got[0].From, "/e")
+	is.Equal(got[1].From, "/c")
+	is.True(!got[0].MovedAt.IsZero())
+
+	for _, rel := range got {
+		is.NoErr(store.MarkRelocationUndone(ctx, rel.ID))
+	}
+
+	got = undoable()
+	is.Equal(len(got), 1) // want the previous batch after undoing the latest
+	is.Equal(got[0].From, "/a")
+}
@@ -4,13 +4,18 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/hay-kot/repomgr/app/core/db"
 	"github.com/hay-kot/repomgr/app/core/db/migrations"
 	"github.com/hay-kot/repomgr/app/repos"
 )
 
-var ErrNoReadmeFound = errors.New("no readme found")
+var (
+	ErrNoReadmeFound      = errors.New("no readme found")
+	ErrRepositoryNotFound = errors.New("repository not found")
+)
 
 type RepoStore struct {
 	sql *sql.DB
@@ -18,7 +23,7 @@ type RepoStore struct {
 }
 
 func New(s *sql.DB) (*RepoStore, error) {
-	_, err := s.Exec(migrations.Schema)
+	err := migrations.Migrate(context.Background(), s)
 	if err != nil {
 		return nil, err
 	}
@@ -32,30 +37,130 @@ func (s *RepoStore) GetAll(ctx context.Context) ([]repos.Repository, error) {
 		return nil, err
 	}
 
+	return s.hydrate(ctx, v)
+}
+
+// GetByDisplayName returns the repository matching the "owner/name" string. If no
+// repository is found ErrRepositoryNotFound is returned.
+func (s *RepoStore) GetByDisplayName(ctx context.Context, displayName string) (repos.Repository, error) {
+	owner, name, err := repos.ParseDisplayName(displayName)
+	if err != nil {
+		return repos.Repository{}, err
+	}
+
+	v, err := s.db.RepoByOwnerName(ctx, db.RepoByOwnerNameParams{
+		Username: owner,
+		Name:     name,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return repos.Repository{}, fmt.Errorf("%w: %s", ErrRepositoryNotFound, displayName)
+		}
+
+		return repos.Repository{}, err
+	}
+
+	results, err := s.hydrate(ctx, []db.Repository{v})
+	if err != nil {
+		return repos.Repository{}, err
+	}
+
+	return results[0], nil
+}
+
+// hydrate maps the database rows into repositories and attaches the local
+// state (pins, tags, notes) for each repository.
+func (s *RepoStore) hydrate(ctx context.Context, v []db.Repository) ([]repos.Repository, error) {
+	pins, err := s.db.PinsGetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pinned := make(map[int64]bool, len(pins))
+	for _, id := range pins {
+		pinned[id] = true
+	}
+
+	tagRows, err := s.db.TagsGetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make(map[int64][]string)
+	for _, row := range tagRows {
+		tags[row.RepositoryID] = append(tags[row.RepositoryID], row.Tag)
+	}
+
+	noteRows, err := s.db.NotesGetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	notes := make(map[int64]string, len(noteRows))
+	for _, row := range noteRows {
+		notes[row.RepositoryID] = row.Note
+	}
+
 	results := make([]repos.Repository, len(v))
 	for i, item := range v {
-		results[i] = repos.Repository{
-			ID:          int(item.ID),
-			RemoteID:    item.RemoteID,
-			Name:        item.Name,
-			Owner:       item.Username,
-			Description: item.Description,
-			HTMLURL:     item.HtmlUrl,
-			CloneURL:    item.CloneUrl,
-			CloneSSHURL: item.CloneSshUrl,
-			IsFork:      item.IsFork,
-			ForkURL:     item.ForkUrl,
-		}
+		results[i] = mapRepository(item)
+		results[i].Pinned = pinned[item.ID]
+		results[i].Tags = tags[item.ID]
+		results[i].Note = notes[item.ID]
 	}
 
 	return results, nil
 }
 
+func mapRepository(item db.Repository) repos.Repository {
+	return repos.Repository{
+		ID:          int(item.ID),
+		RemoteID:    item.RemoteID,
+		Name:        item.Name,
+		Owner:       item.Username,
+		Description: item.Description,
+		HTMLURL:     item.HtmlUrl,
+		CloneURL:    item.CloneUrl,
+		CloneSSHURL: item.CloneSshUrl,
+		IsFork:      item.IsFork,
+		ForkURL:     item.ForkUrl,
+		IsArchived:  item.IsArchived.Bool,
+		Source:      item.Source,
+		SyncedAt:    time.Unix(item.SyncedAt, 0),
+	}
+}
+
+// mapStored maps a stored repository to diff it against next. The archived
+// state of repositories cached before it was synced is unknown and is assumed
+// to match next, so no archive change is reported for them.
+func mapStored(item db.Repository, next repos.Repository) repos.Repository {
+	prev := mapRepository(item)
+	if !item.IsArchived.Valid {
+		prev.IsArchived = next.IsArchived
+	}
+
+	return prev
+}
+
+// UpsertMany inserts or updates the repositories in a single transaction. Each
+// repository is diffed against the stored version and the differences are
+// recorded in the change log.
 func (s *RepoStore) UpsertMany(ctx context.Context, items []repos.Repository) error {
-	// TODO: implement transactions
-	tx := s.db
+	tx, err := s.sql.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	q := s.db.WithTx(tx)
+	now := time.Now()
 	for _, item := range items {
-		_, err := tx.RepoUpsert(ctx, db.RepoUpsertParams{
+		existing, err := q.RepoByRemoteID(ctx, item.RemoteID)
+		if err != nil {
+			return err
+		}
+
+		row, err := q.RepoUpsert(ctx, db.RepoUpsertParams{
 			RemoteID:    item.RemoteID,
 			Name:        item.Name,
 			Username:    item.Owner,
@@ -65,13 +170,37 @@ func (s *RepoStore) UpsertMany(ctx context.Context, items []repos.Repository) er
 			CloneSshUrl: item.CloneSSHURL,
 			IsFork:      item.IsFork,
 			ForkUrl:     item.ForkURL,
+			Source:      item.Source,
+			SyncedAt:    now.Unix(),
+			IsArchived:  sql.NullBool{Bool: item.IsArchived, Valid: true},
 		})
 		if err != nil {
 			return err
 		}
+
+		var changes []Change
+		if len(existing) == 0 {
+			changes = []Change{{Kind: ChangeFirstSeen, New: item.DisplayName()}}
+		} else {
+			changes = DiffRepository(mapStored(existing[0], item), item)
+		}
+
+		for _, change := range changes {
+			err := q.ChangeCreate(ctx, db.ChangeCreateParams{
+				RepositoryID: row.ID,
+				DisplayName:  item.DisplayName(),
+				Kind:         change.Kind.String(),
+				OldValue:     change.Old,
+				NewValue:     change.New,
+				CreatedAt:    now.Unix(),
+			})
+			if err != nil {
+				return err
+			}
+		}
 	}
 
-	return nil
+	return tx.Commit()
 }
 
 func (s *RepoStore) UpsertOne(ctx context.Context, item repos.Repository) error {
@@ -79,30 +208,38 @@ func (s *RepoStore) UpsertOne(ctx context.Context, item repos.Repository) error
 }
 
 func (s *RepoStore) GetReadme(ctx context.Context, repoID int) ([]byte, error) {
-	v, err := s.db.RepoArtifactByType(ctx, db.RepoArtifactByTypeParams{
-		RepositoryID: int64(repoID),
-		DataType:     ArtifactTypeReadme.String(),
-	})
+	v, err := s.GetArtifact(ctx, repoID, ArtifactTypeReadme)
 	if err != nil {
+		if errors.Is(err, ErrNoArtifactFound) {
+			return nil, ErrNoReadmeFound
+		}
+
 		return nil, err
 	}
 
-	if len(v) == 0 || len(v[0].Data) == 0 {
+	if len(v.Data) == 0 {
 		return nil, ErrNoReadmeFound
 	}
 
-	return v[0].Data, nil
+	return v.Data, nil
 }
 
 func (s *RepoStore) SetReadme(ctx context.Context, repoID int, data []byte) error {
-	_, err := s.db.RepoUpsertArtifact(ctx, db.RepoUpsertArtifactParams{
-		RepositoryID: int64(repoID),
-		DataType:     ArtifactTypeReadme.String(),
-		Data:         data,
-	})
+	_, err := s.SetArtifact(ctx, repoID, ArtifactTypeReadme, data)
+	return err
+}
+
+// TogglePin pins the repository if it is not pinned, otherwise it removes the pin.
+// It returns the resulting pinned state.
+func (s *RepoStore) TogglePin(ctx context.Context, repoID int) (bool, error) {
+	pinned, err := s.db.PinExists(ctx, int64(repoID))
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	return err
+	if pinned {
+		return false, s.db.PinDelete(ctx, int64(repoID))
+	}
+
+	return true, s.db.PinCreate(ctx, int64(repoID))
 }
@@ -139,3 +139,55 @@ func Test_RepositoryService_GetReadme(t *testing.T) {
 
 	is.Equal(string(got), "hello world 2")
 }
+
+func Test_RepositoryService_TogglePin(t *testing.T) {
+	service := tRepoStore(t)
+	is := is.New(t)
+
+	err := service.UpsertMany(context.Background(), factory(2))
+	is.NoErr(err)
+
+	all, err := service.GetAll(context.Background())
+	is.NoErr(err)
+
+	target := all[0]
+	is.True(!target.Pinned) // repositories are not pinned by default
+
+	pinned, err := service.TogglePin(context.Background(), target.ID)
+	is.NoErr(err)
+	is.True(pinned) // first toggle pins the repository
+
+	all, err = service.GetAll(context.Background())
+	is.NoErr(err)
+
+	for _, got := range all {
+		is.Equal(got.Pinned, got.ID == target.ID) // only the target should be pinned
+	}
+
+	pinned, err = service.TogglePin(context.Background(), target.ID)
+	is.NoErr(err)
+	is.True(!pinned) // second toggle removes the pin
+
+	all, err = service.GetAll(context.Background())
+	is.NoErr(err)
+
+	for _, got := range all {
+		is.True(!got.Pinned) // no repositories should be pinned
+	}
+}
+
+func Test_New_MigrationsIdempotent(t *testing.T) {
+	is := is.New(t)
+
+	db, err := sql.Open("sqlite", ":memory:")
+	is.NoErr(err)
+
+	// keep a single connection so the in-memory database is shared
+	db.SetMaxOpenConns(1)
+
+	_, err = New(db)
+	is.NoErr(err)
+
+	_, err = New(db)
+	is.NoErr(err) // applying the schema again should be a no-op
+}
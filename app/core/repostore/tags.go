@@ -0,0 +1,84 @@
+package repostore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hay-kot/repomgr/app/core/db"
+)
+
+// NormalizeTag lowercases and trims the tag and validates that it can be used
+// as a tag. Tags cannot be empty or contain whitespace.
+func NormalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return "", fmt.Errorf("tag cannot be empty")
+	}
+
+	if strings.ContainsAny(tag, " \t\n") {
+		return "", fmt.Errorf("tag '%s' cannot contain whitespace", tag)
+	}
+
+	return tag, nil
+}
+
+// AddTags attaches the tags to the repository. Tags that are already attached
+// are ignored.
+func (s *RepoStore) AddTags(ctx context.Context, repoID int, tags ...string) error {
+	for _, tag := range tags {
+		tag, err := NormalizeTag(tag)
+		if err != nil {
+			return err
+		}
+
+		err = s.db.TagCreate(ctx, db.TagCreateParams{
+			RepositoryID: int64(repoID),
+			Tag:          tag,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RemoveTags removes the tags from the repository. Tags that are not attached
+// are ignored.
+func (s *RepoStore) RemoveTags(ctx context.Context, repoID int, tags ...string) error {
+	for _, tag := range tags {
+		tag, err := NormalizeTag(tag)
+		if err != nil {
+			return err
+		}
+
+		err = s.db.TagDelete(ctx, db.TagDeleteParams{
+			RepositoryID: int64(repoID),
+			Tag:          tag,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetTags returns the tags attached to the repository sorted alphabetically.
+func (s *RepoStore) GetTags(ctx context.Context, repoID int) ([]string, error) {
+	return s.db.TagsByRepository(ctx, int64(repoID))
+}
+
+// SetNote sets the note for the repository. An empty note removes the note.
+func (s *RepoStore) SetNote(ctx context.Context, repoID int, note string) error {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return s.db.NoteDelete(ctx, int64(repoID))
+	}
+
+	return s.db.NoteUpsert(ctx, db.NoteUpsertParams{
+		RepositoryID: int64(repoID),
+		Note:         note,
+	})
+}
@@ -0,0 +1,93 @@
+package repostore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func Test_NormalizeTag(t *testing.T) {
+	type tcase struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}
+
+	cases := []tcase{
+		{name: "lowercase", input: "Infra", want: "infra"},
+		{name: "trim", input: "  infra ", want: "infra"},
+		{name: "empty", input: "  ", wantErr: true},
+		{name: "whitespace", input: "on call", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			is := is.New(t)
+
+			got, err := NormalizeTag(tc.input)
+			if tc.wantErr {
+				is.True(err != nil) // expected error
+				return
+			}
+
+			is.NoErr(err)
+			is.Equal(got, tc.want)
+		})
+	}
+}
+
+func Test_RepositoryService_Tags(t *testing.T) {
+	service := tRepoStore(t)
+	is := is.New(t)
+	ctx := context.Background()
+
+	want := factory(1)[0]
+	err := service.UpsertOne(ctx, want)
+	is.NoErr(err)
+
+	got, err := service.GetByDisplayName(ctx, want.DisplayName())
+	is.NoErr(err)
+
+	err = service.AddTags(ctx, got.ID, "infra", "Runbook", "infra")
+	is.NoErr(err)
+
+	tags, err := service.GetTags(ctx, got.ID)
+	is.NoErr(err)
+	is.Equal(tags, []string{"infra", "runbook"}) // tags are normalized and deduplicated
+
+	err = service.RemoveTags(ctx, got.ID, "runbook")
+	is.NoErr(err)
+
+	err = service.SetNote(ctx, got.ID, "owned by platform team")
+	is.NoErr(err)
+
+	// re-syncing the repository should not drop local state
+	err = service.UpsertOne(ctx, want)
+	is.NoErr(err)
+
+	got, err = service.GetByDisplayName(ctx, want.DisplayName())
+	is.NoErr(err)
+	is.Equal(got.Tags, []string{"infra"})
+	is.Equal(got.Note, "owned by platform team")
+
+	err = service.SetNote(ctx, got.ID, "")
+	is.NoErr(err)
+
+	got, err = service.GetByDisplayName(ctx, want.DisplayName())
+	is.NoErr(err)
+	is.Equal(got.Note, "") // empty note removes the note
+}
+
+func Test_RepositoryService_GetByDisplayName_NotFound(t *testing.T) {
+	service := tRepoStore(t)
+	is := is.New(t)
+
+	_, err := service.GetByDisplayName(context.Background(), "owner/missing")
+	is.True(errors.Is(err, ErrRepositoryNotFound)) // missing repository
+
+	_, err = service.GetByDisplayName(context.Background(), "invalid")
+	is.True(err != nil) // invalid display name
+}
@@ -0,0 +1,23 @@
+# repomgr shell integration for bash
+#
+#   eval "$(repomgr shell-init bash)"
+{{ .Name }}() {
+  local tmp dir code
+  tmp="$(mktemp "${TMPDIR:-/tmp}/repomgr.XXXXXX")" || return 1
+  command repomgr search --exit-file "$tmp" "$@"
+  code=$?
+  dir="$(cat -- "$tmp")"
+  rm -f -- "$tmp"
+  if [ -n "$dir" ]; then
+    if [ -d "$dir" ]; then
+      cd -- "$dir" || return 1
+    else
+      printf '%s\n' "$dir"
+    fi
+  fi
+  return $code
+}
+{{- if .Key }}
+
+bind -x '"{{ .Key }}": {{ .Name }}'
+{{- end }}
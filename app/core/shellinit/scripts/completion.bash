@@ -0,0 +1,29 @@
+# repomgr completion for bash
+#
+#   eval "$(repomgr completion bash)"
+_repomgr_init_completion() {
+  COMPREPLY=()
+  _get_comp_words_by_ref "$@" cur prev words cword
+}
+
+_repomgr_complete() {
+  local cur words cword opts request
+  COMPREPLY=()
+  cur="${COMP_WORDS[COMP_CWORD]}"
+  if declare -F _init_completion >/dev/null 2>&1; then
+    _init_completion -n "=:" || return
+  else
+    _repomgr_init_completion -n "=:" || return
+  fi
+  words=("${words[@]:0:$cword}")
+  if [[ "$cur" == "-"* ]]; then
+    request="${words[*]} ${cur} --generate-bash-completion"
+  else
+    request="${words[*]} --generate-bash-completion"
+  fi
+  opts=$(eval "${request}" 2>/dev/null)
+  COMPREPLY=($(compgen -W "${opts}" -- "${cur}"))
+  return 0
+}
+
+complete -o bashdefault -o default -o nospace -F _repomgr_complete repomgr
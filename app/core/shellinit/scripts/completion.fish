@@ -0,0 +1,13 @@
+# repomgr completion for fish
+#
+#   repomgr completion fish | source
+function __repomgr_complete
+    set -l args (commandline -opc)
+    set -l cur (commandline -ct)
+    if string match -q -- '-*' $cur
+        set -a args $cur
+    end
+    $args --generate-bash-completion 2>/dev/null
+end
+
+complete -c repomgr -f -a '(__repomgr_complete)'
@@ -0,0 +1,23 @@
+# repomgr shell integration for fish
+#
+#   repomgr shell-init fish | source
+function {{ .Name }} --description 'search repositories and cd into the selection'
+    set -l tmp (mktemp)
+    or return 1
+    command repomgr search --exit-file $tmp $argv
+    set -l code $status
+    set -l dir (cat $tmp)
+    rm -f $tmp
+    if test -n "$dir"
+        if test -d "$dir"
+            cd $dir
+        else
+            echo $dir
+        end
+    end
+    return $code
+end
+{{- if .Key }}
+
+bind {{ .Key }} '{{ .Name }}; commandline -f repaint'
+{{- end }}
@@ -0,0 +1,28 @@
+# repomgr shell integration for zsh
+#
+#   eval "$(repomgr shell-init zsh)"
+{{ .Name }}() {
+  local tmp dir code
+  tmp="$(mktemp "${TMPDIR:-/tmp}/repomgr.XXXXXX")" || return 1
+  command repomgr search --exit-file "$tmp" "$@"
+  code=$?
+  dir="$(cat -- "$tmp")"
+  rm -f -- "$tmp"
+  if [ -n "$dir" ]; then
+    if [ -d "$dir" ]; then
+      cd -- "$dir" || return 1
+    else
+      printf '%s\n' "$dir"
+    fi
+  fi
+  return $code
+}
+{{- if .Key }}
+
+_{{ .Name }}_widget() {
+  {{ .Name }} </dev/tty
+  zle reset-prompt
+}
+zle -N _{{ .Name }}_widget
+bindkey '{{ .Key }}' _{{ .Name }}_widget
+{{- end }}
@@ -0,0 +1,158 @@
+// Package shellinit renders the shell integration scripts that wrap the search
+// UI and change the working directory of the calling shell to the selection.
+package shellinit
+
+import (
+	"embed"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+//go:embed scripts/*
+var scripts embed.FS
+
+// Shell is a supported shell for the integration scripts.
+type Shell string
+
+const (
+	ShellBash Shell = "bash"
+	ShellZsh  Shell = "zsh"
+	ShellFish Shell = "fish"
+)
+
+// Shells returns the supported shells.
+func Shells() []Shell {
+	return []Shell{ShellBash, ShellZsh, ShellFish}
+}
+
+func (s Shell) script() string {
+	switch s {
+	case ShellFish:
+		return "scripts/fish.fish"
+	default:
+		return "scripts/" + string(s) + ".sh"
+	}
+}
+
+// IsValid returns true if the shell is supported.
+func (s Shell) IsValid() bool {
+	switch s {
+	case ShellBash, ShellZsh, ShellFish:
+		return true
+	default:
+		return false
+	}
+}
+
+// DefaultName is the default name of the shell function.
+const DefaultName = "rcd"
+
+// Options are the options for rendering a shell integration script.
+type Options struct {
+	// Name is the name of the shell function, defaults to DefaultName.
+	Name string
+	// Key is an optional key, in the same "ctrl+<key>" format as the key
+	// bindings, that runs the function from the prompt.
+	Key string
+}
+
+var (
+	reName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+	reKey  = regexp.MustCompile(`^ctrl\+([a-z])$`)
+)
+
+// Render returns the integration script for the shell.
+func Render(shell Shell, opts Options) (string, error) {
+	if !shell.IsValid() {
+		return "", fmt.Errorf("unsupported shell '%s', expected one of %v", shell, Shells())
+	}
+
+	if opts.Name == "" {
+		opts.Name = DefaultName
+	}
+
+	if !reName.MatchString(opts.Name) {
+		return "", fmt.Errorf("invalid function name '%s'", opts.Name)
+	}
+
+	key := ""
+	if opts.Key != "" {
+		var err error
+		key, err = shellKey(shell, opts.Key)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	tmpl, err := template.ParseFS(scripts, shell.script())
+	if err != nil {
+		return "", err
+	}
+
+	bldr := strings.Builder{}
+	err = tmpl.Execute(&bldr, map[string]string{
+		"Name": opts.Name,
+		"Key":  key,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return bldr.String(), nil
+}
+
+// Completion returns the completion script for the shell. The scripts complete
+// commands, flags and arguments by calling back into repomgr with the
+// --generate-bash-completion flag, so repository names are completed from the
+// cache.
+func Completion(shell Shell) (string, error) {
+	if !shell.IsValid() {
+		return "", fmt.Errorf("unsupported shell '%s', expected one of %v", shell, Shells())
+	}
+
+	data, err := scripts.ReadFile("scripts/completion." + string(shell))
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// shellKey translates a "ctrl+<key>" binding into the syntax of the shell.
+func shellKey(shell Shell, key string) (string, error) {
+	m := reKey.FindStringSubmatch(strings.ToLower(key))
+	if m == nil {
+		return "", fmt.Errorf("invalid key '%s', expected the format 'ctrl+<letter>'", key)
+	}
+
+	switch shell {
+	case ShellBash:
+		return `\C-` + m[1], nil
+	case ShellZsh:
+		return "^" + strings.ToUpper(m[1]), nil
+	case ShellFish:
+		return `\c` + m[1], nil
+	default:
+		return "", fmt.Errorf("unsupported shell '%s'", shell)
+	}
+}
+
+// Unquote removes a single pair of matching quotes surrounding the exit
+// message. The default exit binding quotes the clone directory so the
+// message can be pasted into a shell, which the integration scripts do not
+// expect when reading it back as a path.
+func Unquote(msg string) string {
+	msg = strings.TrimSpace(msg)
+	if len(msg) < 2 {
+		return msg
+	}
+
+	first, last := msg[0], msg[len(msg)-1]
+	if first == last && (first == '\'' || first == '"') {
+		return msg[1 : len(msg)-1]
+	}
+
+	return msg
+}
@@ -0,0 +1,116 @@
+package shellinit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func Test_Render(t *testing.T) {
+	tests := []struct {
+		name     string
+		shell    Shell
+		opts     Options
+		contains []string
+		wantErr  bool
+	}{
+		{
+			name:     "bash default name",
+			shell:    ShellBash,
+			contains: []string{"rcd() {", "--exit-file"},
+		},
+		{
+			name:     "bash with key",
+			shell:    ShellBash,
+			opts:     Options{Name: "repo", Key: "ctrl+f"},
+			contains: []string{"repo() {", `bind -x '"\C-f": repo'`},
+		},
+		{
+			name:     "zsh with key",
+			shell:    ShellZsh,
+			opts:     Options{Key: "ctrl+f"},
+			contains: []string{"zle -N _rcd_widget", "bindkey '^F' _rcd_widget"},
+		},
+		{
+			name:     "fish with key",
+			shell:    ShellFish,
+			opts:     Options{Key: "ctrl+f"},
+			contains: []string{"function rcd", `bind \cf 'rcd; commandline -f repaint'`},
+		},
+		{
+			name:    "unsupported shell",
+			shell:   Shell("pwsh"),
+			wantErr: true,
+		},
+		{
+			name:    "invalid name",
+			shell:   ShellBash,
+			opts:    Options{Name: "rm -rf"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid key",
+			shell:   ShellBash,
+			opts:    Options{Key: "alt+f"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			is := is.New(t)
+
+			got, err := Render(tt.shell, tt.opts)
+			if tt.wantErr {
+				is.True(err != nil) // want error
+				return
+			}
+
+			is.NoErr(err)
+			for _, want := range tt.contains {
+				is.True(strings.Contains(got, want)) // want script to contain snippet
+			}
+
+			if tt.opts.Key == "" {
+				is.True(!strings.Contains(got, "bind")) // want no key binding
+			}
+		})
+	}
+}
+
+func Test_Unquote(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "'/home/user/src'", want: "/home/user/src"},
+		{in: `"/home/user/src"`, want: "/home/user/src"},
+		{in: "/home/user/src\n", want: "/home/user/src"},
+		{in: "'/home/user/src", want: "'/home/user/src"},
+		{in: "'", want: "'"},
+		{in: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			is := is.New(t)
+			is.Equal(Unquote(tt.in), tt.want)
+		})
+	}
+}
+
+func Test_Completion(t *testing.T) {
+	for _, shell := range Shells() {
+		t.Run(string(shell), func(t *testing.T) {
+			is := is.New(t)
+
+			got, err := Completion(shell)
+			is.NoErr(err)
+			is.True(strings.Contains(got, "--generate-bash-completion")) // want dynamic completion
+		})
+	}
+
+	_, err := Completion(Shell("pwsh"))
+	is.New(t).True(err != nil) // want error for unsupported shell
+}
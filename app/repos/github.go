@@ -2,6 +2,7 @@ package repos
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -50,18 +51,19 @@ func (g *GithubClient) mapRepository(repo *github.Repository) Repository {
 		CloneSSHURL: repo.GetSSHURL(),
 		IsFork:      repo.GetFork(),
 		ForkURL:     fork_url,
+		IsArchived:  repo.GetArchived(),
 	}
 }
 
 // GetAllByUsername implements RepositoryClient.
-func (g *GithubClient) GetAllByUsername(ctx context.Context, username string) ([]Repository, error) {
+func (g *GithubClient) GetAllByUsername(ctx context.Context, username string, onPage PageFunc) ([]Repository, error) {
 	opt := &github.RepositoryListByAuthenticatedUserOptions{
 		Type:        "all",
 		ListOptions: github.ListOptions{PerPage: 200},
 	}
 	// get all pages of results
 	var allRepos []*github.Repository
-	for {
+	for page := 1; ; page++ {
 		repos, resp, err := g.client.Repositories.ListByAuthenticatedUser(ctx, opt)
 		if err != nil {
 			log.Err(err).Ctx(ctx).
@@ -70,6 +72,10 @@ func (g *GithubClient) GetAllByUsername(ctx context.Context, username string) ([
 			return nil, err
 		}
 		allRepos = append(allRepos, repos...)
+		if onPage != nil {
+			onPage(page, len(repos))
+		}
+
 		if resp.NextPage == 0 {
 			break
 		}
@@ -111,3 +117,98 @@ func (g *GithubClient) GetReadme(ctx context.Context, username string, name stri
 
 	return content.GetContent()
 }
+
+// isNotFound returns true if the error is a 404 response from the Github API.
+func isNotFound(err error) bool {
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) {
+		return errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound
+	}
+
+	return false
+}
+
+// GetLanguages implements RepositoryClient.
+func (g *GithubClient) GetLanguages(ctx context.Context, username string, name string) (map[string]int, error) {
+	languages, _, err := g.client.Repositories.ListLanguages(ctx, username, name)
+	if err != nil {
+		log.Err(err).Ctx(ctx).
+			Str("username", username).
+			Str("name", name).
+			Msg("failed to get languages")
+		return nil, err
+	}
+
+	return languages, nil
+}
+
+// GetLatestRelease implements RepositoryClient.
+func (g *GithubClient) GetLatestRelease(ctx context.Context, username string, name string) (Release, error) {
+	release, _, err := g.client.Repositories.GetLatestRelease(ctx, username, name)
+	if err != nil {
+		if isNotFound(err) {
+			return Release{}, nil
+		}
+
+		log.Err(err).Ctx(ctx).
+			Str("username", username).
+			Str("name", name).
+			Msg("failed to get latest release")
+		return Release{}, err
+	}
+
+	return Release{
+		TagName:     release.GetTagName(),
+		Name:        release.GetName(),
+		HTMLURL:     release.GetHTMLURL(),
+		PublishedAt: release.GetPublishedAt().Time,
+	}, nil
+}
+
+// GetCodeOwners implements RepositoryClient.
+func (g *GithubClient) GetCodeOwners(ctx context.Context, username string, name string) (string, error) {
+	// locations are checked in the same order as Github
+	paths := []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+	for _, path := range paths {
+		content, _, _, err := g.client.Repositories.GetContents(ctx, username, name, path, nil)
+		if err != nil {
+			if isNotFound(err) {
+				continue
+			}
+
+			log.Err(err).Ctx(ctx).
+				Str("username", username).
+				Str("name", name).
+				Str("path", path).
+				Msg("failed to get codeowners")
+			return "", err
+		}
+
+		if content == nil {
+			continue
+		}
+
+		return content.GetContent()
+	}
+
+	return "", nil
+}
+
+// GetLicense implements RepositoryClient.
+func (g *GithubClient) GetLicense(ctx context.Context, username string, name string) (string, error) {
+	license, _, err := g.client.Repositories.License(ctx, username, name)
+	if err != nil {
+		if isNotFound(err) {
+			return "", nil
+		}
+
+		log.Err(err).Ctx(ctx).
+			Str("username", username).
+			Str("name", name).
+			Msg("failed to get license")
+		return "", err
+	}
+
+	return license.GetLicense().GetSPDXID(), nil
+}
@@ -2,15 +2,48 @@ package repos
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 )
 
+// PageFunc is called by the client after every page of repositories fetched
+// from the remote source with the 1-based page number and the number of
+// repositories on the page.
+type PageFunc func(page, count int)
+
 type RepositoryClient interface {
-	GetAllByUsername(ctx context.Context, username string) ([]Repository, error)
+	// GetAllByUsername returns all repositories of the user, onPage is called
+	// after every page fetched if not nil.
+	GetAllByUsername(ctx context.Context, username string, onPage PageFunc) ([]Repository, error)
 	GetOneByUsername(ctx context.Context, username, name string) (Repository, error)
 
 	// GetReadme returns the README.md content of the repostiroy if it's present.
 	// If the README.md is not present, it returns an empty string.
 	GetReadme(ctx context.Context, username, name string) (string, error)
+
+	// GetLanguages returns the languages used in the repository mapped to the
+	// number of bytes of code written in that language.
+	GetLanguages(ctx context.Context, username, name string) (map[string]int, error)
+
+	// GetLatestRelease returns the latest published release of the repository.
+	// If the repository has no releases, it returns a zero value Release.
+	GetLatestRelease(ctx context.Context, username, name string) (Release, error)
+
+	// GetCodeOwners returns the content of the CODEOWNERS file of the repository.
+	// If no CODEOWNERS file is present, it returns an empty string.
+	GetCodeOwners(ctx context.Context, username, name string) (string, error)
+
+	// GetLicense returns the SPDX identifier of the repository license. If no
+	// license is detected, it returns an empty string.
+	GetLicense(ctx context.Context, username, name string) (string, error)
+}
+
+type Release struct {
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	HTMLURL     string    `json:"html_url"`
+	PublishedAt time.Time `json:"published_at"`
 }
 
 type Repository struct {
@@ -24,9 +57,43 @@ type Repository struct {
 	CloneSSHURL string
 	IsFork      bool
 	ForkURL     string
+	IsArchived  bool
+
+	// Source is the identifier of the configured source the repository was
+	// synced from, and SyncedAt is the last time it was seen in that source.
+	Source   string
+	SyncedAt time.Time
+
+	// Pinned, Tags and Note are local state managed by the user. They are not
+	// provided by the remote source.
+	Pinned bool
+	Tags   []string
+	Note   string
 }
 
 // DisplayName returns the owner and the name of the repository in the format of "owner/name".
 func (r Repository) DisplayName() string {
 	return r.Owner + "/" + r.Name
 }
+
+// HasTag returns true if the repository has been tagged with the given tag.
+func (r Repository) HasTag(tag string) bool {
+	for _, t := range r.Tags {
+		if t == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseDisplayName splits a string in the format of "owner/name" into the owner
+// and name parts.
+func ParseDisplayName(s string) (owner, name string, err error) {
+	owner, name, ok := strings.Cut(s, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("invalid repository name '%s', expected 'owner/name'", s)
+	}
+
+	return owner, name, nil
+}
@@ -0,0 +1,47 @@
+// Package duration parses human friendly durations used in CLI flags.
+package duration
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	Day  = 24 * time.Hour
+	Week = 7 * Day
+)
+
+// Parse parses a duration string. In addition to the units supported by
+// time.ParseDuration, the "d" (day) and "w" (week) units are supported as a
+// single integer value, e.g. "30d" or "2w".
+func Parse(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+
+	units := map[string]time.Duration{
+		"d": Day,
+		"w": Week,
+	}
+
+	for suffix, unit := range units {
+		v, ok := strings.CutSuffix(s, suffix)
+		if !ok {
+			continue
+		}
+
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration '%s'", s)
+		}
+
+		return time.Duration(n) * unit, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration '%s'", s)
+	}
+
+	return d, nil
+}
@@ -0,0 +1,41 @@
+package duration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+)
+
+func Test_Parse(t *testing.T) {
+	type tcase struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}
+
+	cases := []tcase{
+		{input: "30d", want: 30 * Day},
+		{input: "2w", want: 2 * Week},
+		{input: "90m", want: 90 * time.Minute},
+		{input: "1h30m", want: 90 * time.Minute},
+		{input: "d", wantErr: true},
+		{input: "1.5d", wantErr: true},
+		{input: "soon", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			is := is.New(t)
+
+			got, err := Parse(tc.input)
+			if tc.wantErr {
+				is.True(err != nil) // expected error
+				return
+			}
+
+			is.NoErr(err)
+			is.Equal(got, tc.want)
+		})
+	}
+}
@@ -7,6 +7,7 @@ const (
 	Fork   = "\U000f062c" // 󰘬
 	Branch = "\U000f062d" // 󰘭
 	Folder = ""
+	Pin    = "\uf435" // 
 	Stop   = "■"
 	Dot    = "•"
 )
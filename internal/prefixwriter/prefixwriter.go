@@ -0,0 +1,75 @@
+// Package prefixwriter prefixes every line written to an io.Writer, used to
+// interleave the output of commands running in parallel.
+package prefixwriter
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// Writer buffers partial lines and writes each complete line to the
+// underlying writer with the prefix. Writers that share a mutex never
+// interleave within a line.
+type Writer struct {
+	w      io.Writer
+	mu     *sync.Mutex
+	prefix []byte
+	buf    []byte
+}
+
+// New returns a Writer that writes lines to w with the prefix while holding mu.
+func New(w io.Writer, mu *sync.Mutex, prefix string) *Writer {
+	return &Writer{
+		w:      w,
+		mu:     mu,
+		prefix: []byte(prefix),
+	}
+}
+
+// Write implements io.Writer.
+func (pw *Writer) Write(p []byte) (int, error) {
+	pw.buf = append(pw.buf, p...)
+
+	idx := bytes.LastIndexByte(pw.buf, '\n')
+	if idx == -1 {
+		return len(p), nil
+	}
+
+	lines := pw.buf[:idx+1]
+	err := pw.write(lines)
+
+	pw.buf = append(pw.buf[:0], pw.buf[idx+1:]...)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Flush writes the remaining partial line, if any, terminated by a newline.
+func (pw *Writer) Flush() error {
+	if len(pw.buf) == 0 {
+		return nil
+	}
+
+	err := pw.write(append(pw.buf, '\n'))
+	pw.buf = pw.buf[:0]
+	return err
+}
+
+func (pw *Writer) write(lines []byte) error {
+	out := make([]byte, 0, len(lines)+len(pw.prefix)*bytes.Count(lines, []byte{'\n'}))
+	for len(lines) > 0 {
+		idx := bytes.IndexByte(lines, '\n')
+		out = append(out, pw.prefix...)
+		out = append(out, lines[:idx+1]...)
+		lines = lines[idx+1:]
+	}
+
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+
+	_, err := pw.w.Write(out)
+	return err
+}
@@ -0,0 +1,64 @@
+package prefixwriter
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func Test_Writer(t *testing.T) {
+	is := is.New(t)
+
+	var (
+		buf bytes.Buffer
+		mu  sync.Mutex
+	)
+
+	pw := New(&buf, &mu, "a | ")
+
+	_, err := io.WriteString(pw, "one\ntw")
+	is.NoErr(err)
+	is.Equal(buf.String(), "a | one\n") // want partial line to be buffered
+
+	_, err = io.WriteString(pw, "o\nthree\nfour")
+	is.NoErr(err)
+	is.Equal(buf.String(), "a | one\na | two\na | three\n")
+
+	is.NoErr(pw.Flush())
+	is.Equal(buf.String(), "a | one\na | two\na | three\na | four\n") // want flush to terminate the line
+
+	is.NoErr(pw.Flush())
+	is.Equal(buf.String(), "a | one\na | two\na | three\na | four\n") // want no output for empty buffer
+}
+
+func Test_Writer_Shared(t *testing.T) {
+	is := is.New(t)
+
+	var (
+		buf bytes.Buffer
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+	)
+
+	for _, prefix := range []string{"a ", "b "} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			pw := New(&buf, &mu, prefix)
+			for i := 0; i < 100; i++ {
+				_, _ = io.WriteString(pw, "line\n")
+			}
+		}()
+	}
+	wg.Wait()
+
+	lines := bytes.Split(bytes.TrimSuffix(buf.Bytes(), []byte{'\n'}), []byte{'\n'})
+	is.Equal(len(lines), 200)
+	for _, line := range lines {
+		is.True(string(line) == "a line" || string(line) == "b line") // want lines to never interleave
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -19,6 +20,8 @@ import (
 	"github.com/hay-kot/repomgr/app/commands"
 	"github.com/hay-kot/repomgr/app/console"
 	"github.com/hay-kot/repomgr/app/core/config"
+	"github.com/hay-kot/repomgr/app/core/shellinit"
+	"github.com/hay-kot/repomgr/internal/duration"
 )
 
 var (
@@ -37,6 +40,131 @@ func build() string {
 	return fmt.Sprintf("%s (%s) %s", version, short, date)
 }
 
+// formatFromPath returns the format if set, otherwise the format is inferred from
+// the file extension of the path, falling back to json.
+func formatFromPath(format, path string) string {
+	if format != "" {
+		return format
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return commands.FormatCSV
+	}
+
+	return commands.FormatJSON
+}
+
+func maintenanceFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "print the changes without applying them",
+		},
+		&cli.BoolFlag{
+			Name:    "yes",
+			Aliases: []string{"y"},
+			Usage:   "skip the confirmation prompt",
+		},
+	}
+}
+
+func maintenanceOptions(ctx *cli.Context) commands.MaintenanceOptions {
+	return commands.MaintenanceOptions{
+		DryRun: ctx.Bool("dry-run"),
+		Yes:    ctx.Bool("yes"),
+	}
+}
+
+// filterFlags are the shared flags for commands that operate on a filtered set
+// of repositories from the cache.
+func filterFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:    "match",
+			Aliases: []string{"m"},
+			Usage:   "only include repositories matching the owner/name glob (e.g. 'acme/*')",
+		},
+		&cli.StringSliceFlag{
+			Name:  "owner",
+			Usage: "only include repositories of the owner",
+		},
+		&cli.BoolFlag{
+			Name:  "fork",
+			Usage: "only include forks, --fork=false excludes forks",
+		},
+		&cli.BoolFlag{
+			Name:  "cloned",
+			Usage: "only include cloned repositories, --cloned=false only includes repositories that are not cloned",
+		},
+		&cli.StringSliceFlag{
+			Name:  "language",
+			Usage: "only include repositories using the language (requires the repo.languages artifact)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "tag",
+			Usage: "only include repositories with the tag",
+		},
+	}
+}
+
+func repoFilter(ctx *cli.Context) commands.RepoFilter {
+	f := commands.RepoFilter{
+		Match:     ctx.StringSlice("match"),
+		Owners:    ctx.StringSlice("owner"),
+		Languages: ctx.StringSlice("language"),
+		Tags:      ctx.StringSlice("tag"),
+	}
+
+	if ctx.IsSet("fork") {
+		v := ctx.Bool("fork")
+		f.Fork = &v
+	}
+
+	if ctx.IsSet("cloned") {
+		v := ctx.Bool("cloned")
+		f.Cloned = &v
+	}
+
+	return f
+}
+
+// requiresConfig returns false for commands that must work without a config
+// file, such as the shell integration which is evaluated at shell startup and
+// the wizard that creates the config. The doctor loads the config itself to
+// report the error.
+func requiresConfig(ctx *cli.Context) bool {
+	switch ctx.Args().First() {
+	case "shell-init", "completion", "doctor":
+		return false
+	case "config":
+		return ctx.Args().Get(1) != "init"
+	default:
+		return true
+	}
+}
+
+// completingFlag returns true if the shell is completing a flag name, the
+// completion scripts append the partial flag before --generate-bash-completion.
+func completingFlag() bool {
+	if len(os.Args) < 3 {
+		return false
+	}
+
+	return strings.HasPrefix(os.Args[len(os.Args)-2], "-")
+}
+
+// completeShells completes the supported shells for the shell integration
+// commands.
+func completeShells(ctx *cli.Context) {
+	if ctx.NArg() > 0 {
+		return
+	}
+
+	for _, shell := range shellinit.Shells() {
+		fmt.Fprintln(ctx.App.Writer, shell)
+	}
+}
+
 func main() {
 	appctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer cancel()
@@ -45,111 +173,680 @@ func main() {
 
 	cons := console.NewConsole(os.Stdout, true)
 
-	app := &cli.App{
-		Name:    "Repo Manager",
-		Usage:   "Repository Management TUI/CLI for working with Github Projects",
-		Version: build(),
-		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:    "config",
-				Usage:   "config file",
-				Value:   config.ExpandPath("", "~/.config/repomgr/config.toml"),
-				EnvVars: []string{"REPOMGR_CONFIG"},
-			},
-		},
-		Before: func(ctx *cli.Context) error {
-			p := ctx.String("config")
-			f, err := os.Open(p)
+	// withCtrl opens the database and constructs a controller for the
+	// duration of the command action.
+	withCtrl := func(fn func(ctx *cli.Context, ctrl *commands.Controller) error) cli.ActionFunc {
+		return func(ctx *cli.Context) error {
+			db, err := sql.Open("sqlite", cfg.Database.DNS())
 			if err != nil {
-				return fmt.Errorf("failed to open config file: %w", err)
+				return err
 			}
+			defer db.Close()
 
-			defer f.Close()
-
-			absolutePath, err := filepath.Abs(p)
+			ctrl, err := commands.NewController(cfg, db, cons)
 			if err != nil {
 				return err
 			}
 
-			cfg, err = config.New(absolutePath, f)
-			if err != nil {
-				return err
+			return fn(ctx, ctrl)
+		}
+	}
+
+	// readConfig reads, decodes and validates the config file from the
+	// --config flag.
+	readConfig := func(ctx *cli.Context) error {
+		p := ctx.String("config")
+		f, err := os.Open(p)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("config file %s does not exist, run 'repomgr config init' to create it", p)
 			}
 
-			err = cfg.PrepareDirectories()
+			return fmt.Errorf("failed to open config file: %w", err)
+		}
+
+		defer f.Close()
+
+		absolutePath, err := filepath.Abs(p)
+		if err != nil {
+			return err
+		}
+
+		cfg, err = config.New(absolutePath, f)
+		return err
+	}
+
+	// setupEnvironment creates the directories of the loaded config and sets
+	// up the logger.
+	setupEnvironment := func() error {
+		err := cfg.PrepareDirectories()
+		if err != nil {
+			return fmt.Errorf("failed to create directories: %w", err)
+		}
+
+		var writer io.Writer
+
+		logFile := cfg.Logs.File
+		if logFile == "" {
+			writer = io.Discard
+		} else {
+			f, err := os.OpenFile(logFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
 			if err != nil {
-				return err
+				return fmt.Errorf("failed to open log file: %w", err)
 			}
+			writer = f
+		}
 
-			var writer io.Writer
+		if cfg.Logs.Format == "text" {
+			log.Logger = log.Output(zerolog.ConsoleWriter{
+				Out:     writer,
+				NoColor: !cfg.Logs.Color,
+			})
+		} else if cfg.Logs.Format == "json" {
+			log.Logger = log.Output(writer)
+		}
 
-			logFile := cfg.Logs.File
-			if logFile == "" {
-				writer = io.Discard
-			} else {
-				f, err := os.OpenFile(logFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
-				if err != nil {
-					return err
-				}
-				writer = f
+		zerolog.SetGlobalLevel(cfg.Logs.Level)
+		return nil
+	}
+
+	// loadConfig reads the config file from the --config flag and sets up the
+	// environment. It is called before every command that requires a config
+	// and by the shell completions, which skip the Before hook.
+	loadConfig := func(ctx *cli.Context) error {
+		err := readConfig(ctx)
+		if err != nil {
+			return err
+		}
+
+		err = setupEnvironment()
+		if err != nil {
+			return err
+		}
+
+		log.Debug().Str("config", ctx.String("config")).Msg("loaded config")
+		return nil
+	}
+
+	// completeWith returns a shell completion function that writes the
+	// suggestions for the command arguments using fn. Flags are completed with
+	// the default completion and errors are ignored so completion never prints
+	// anything unexpected.
+	completeWith := func(fn func(ctx *cli.Context, ctrl *commands.Controller) error) cli.BashCompleteFunc {
+		return func(ctx *cli.Context) {
+			if completingFlag() {
+				cli.DefaultCompleteWithFlags(ctx.Command)(ctx)
+				return
 			}
 
-			if cfg.Logs.Format == "text" {
-				log.Logger = log.Output(zerolog.ConsoleWriter{
-					Out:     writer,
-					NoColor: !cfg.Logs.Color,
-				})
-			} else if cfg.Logs.Format == "json" {
-				log.Logger = log.Output(writer)
+			if err := loadConfig(ctx); err != nil {
+				return
 			}
 
-			zerolog.SetGlobalLevel(cfg.Logs.Level)
-			log.Debug().Str("config", absolutePath).Msg("loaded config")
+			_ = withCtrl(fn)(ctx)
+		}
+	}
+
+	completeRepos := completeWith(func(ctx *cli.Context, ctrl *commands.Controller) error {
+		return ctrl.CompleteRepos(appctx, ctx.App.Writer)
+	})
+
+	// completeRepoArg only completes the first argument, for commands where
+	// the repository is followed by other values.
+	completeRepoArg := completeWith(func(ctx *cli.Context, ctrl *commands.Controller) error {
+		if ctx.NArg() > 0 {
 			return nil
+		}
+
+		return ctrl.CompleteRepos(appctx, ctx.App.Writer)
+	})
+
+	app := &cli.App{
+		Name:    "Repo Manager",
+		Usage:   "Repository Management TUI/CLI for working with Github Projects",
+		Version: build(),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Usage:   "config file",
+				Value:   config.ExpandPath("", "~/.config/repomgr/config.toml"),
+				EnvVars: []string{"REPOMGR_CONFIG"},
+			},
+		},
+		EnableBashCompletion: true,
+		Before: func(ctx *cli.Context) error {
+			if !requiresConfig(ctx) {
+				return nil
+			}
+
+			return loadConfig(ctx)
 		},
 		Commands: []*cli.Command{
 			{
 				Name:  "cache",
 				Usage: "cache controls for the database",
-				Action: func(ctx *cli.Context) error {
-					db, err := sql.Open("sqlite", cfg.Database.DNS())
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "output",
+						Usage: "progress output (text, json), json writes one event per line",
+						Value: commands.FormatText,
+					},
+					&cli.BoolFlag{
+						Name:  "strict",
+						Usage: "fail if any source fails to sync, by default the sync only fails if every source fails",
+					},
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "fetch the sources and print the changes to the cache without saving them",
+					},
+				},
+				Action: withCtrl(func(ctx *cli.Context, ctrl *commands.Controller) error {
+					return ctrl.Cache(appctx, ctx.App.Writer, commands.CacheOptions{
+						Output: ctx.String("output"),
+						Strict: ctx.Bool("strict"),
+						DryRun: ctx.Bool("dry-run"),
+					})
+				}),
+				Subcommands: []*cli.Command{
+					{
+						Name:  "clear",
+						Usage: "remove all repositories, or the repositories of a source, from the cache",
+						Flags: append(maintenanceFlags(), &cli.StringFlag{
+							Name:  "source",
+							Usage: "only clear repositories synced from this source (username or type/username)",
+						}),
+						Action: withCtrl(func(ctx *cli.Context, ctrl *commands.Controller) error {
+							return ctrl.CacheClear(appctx, ctx.String("source"), maintenanceOptions(ctx))
+						}),
+					},
+					{
+						Name:  "vacuum",
+						Usage: "rebuild the database file to reclaim unused space",
+						Flags: maintenanceFlags(),
+						Action: withCtrl(func(ctx *cli.Context, ctrl *commands.Controller) error {
+							return ctrl.CacheVacuum(appctx, maintenanceOptions(ctx))
+						}),
+					},
+					{
+						Name:  "prune",
+						Usage: "remove repositories that have not been seen in a sync recently",
+						Flags: append(maintenanceFlags(), &cli.StringFlag{
+							Name:  "stale",
+							Usage: "remove repositories not synced within this duration (e.g. 30d, 2w, 12h)",
+							Value: "30d",
+						}),
+						Action: withCtrl(func(ctx *cli.Context, ctrl *commands.Controller) error {
+							stale, err := duration.Parse(ctx.String("stale"))
+							if err != nil {
+								return err
+							}
+
+							return ctrl.CachePrune(appctx, stale, maintenanceOptions(ctx))
+						}),
+					},
+					{
+						Name:  "verify",
+						Usage: "check the database integrity and remove orphaned rows",
+						Flags: maintenanceFlags(),
+						Action: withCtrl(func(ctx *cli.Context, ctrl *commands.Controller) error {
+							return ctrl.CacheVerify(appctx, maintenanceOptions(ctx))
+						}),
+					},
+				},
+			},
+			{
+				Name:  "search",
+				Usage: "search for repositories",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "query",
+						Aliases: []string{"q"},
+						Usage:   "run the search without the UI and print the matches",
+					},
+					&cli.BoolFlag{
+						Name:  "first",
+						Usage: "only print the top match, same as --limit 1",
+					},
+					&cli.IntFlag{
+						Name:  "limit",
+						Usage: "maximum number of matches to print, 0 prints all",
+					},
+					&cli.StringFlag{
+						Name:  "print",
+						Usage: "value to print for each match (name, clone-dir, url, clone-url, ssh-url)",
+						Value: commands.PrintName,
+					},
+					&cli.StringFlag{
+						Name:  "exit-file",
+						Usage: "write the exit message to this file instead of stdout (used by shell-init)",
+					},
+				},
+				Action: withCtrl(func(ctx *cli.Context, ctrl *commands.Controller) error {
+					if ctx.IsSet("query") || ctx.IsSet("first") || ctx.IsSet("limit") {
+						limit := ctx.Int("limit")
+						if ctx.Bool("first") {
+							limit = 1
+						}
+
+						return ctrl.SearchQuery(appctx, os.Stdout, ctx.String("query"), limit, ctx.String("print"))
+					}
+
+					msg, err := ctrl.Search(appctx)
 					if err != nil {
 						return err
 					}
-					defer db.Close()
 
-					ctrl, err := commands.NewController(cfg, db)
+					if p := ctx.String("exit-file"); p != "" {
+						return os.WriteFile(p, []byte(shellinit.Unquote(msg)), 0o600)
+					}
+
+					if msg != "" {
+						fmt.Println(msg)
+					}
+
+					return nil
+				}),
+			},
+			{
+				Name:  "changes",
+				Usage: "report repositories that appeared or changed in recent syncs",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "since",
+						Usage: "report changes within this duration (e.g. 7d, 2w, 12h)",
+						Value: "7d",
+					},
+					&cli.StringSliceFlag{
+						Name:  "kind",
+						Usage: "only report changes of this kind (first_seen, renamed, transferred, archived, unarchived, description_changed)",
+					},
+				},
+				Action: withCtrl(func(ctx *cli.Context, ctrl *commands.Controller) error {
+					since, err := duration.Parse(ctx.String("since"))
 					if err != nil {
 						return err
 					}
-					return ctrl.Cache(appctx)
+
+					return ctrl.Changes(appctx, since, ctx.StringSlice("kind"))
+				}),
+			},
+			{
+				Name:  "export",
+				Usage: "export repositories, artifacts, tags and pins",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "format",
+						Aliases: []string{"f"},
+						Usage:   "output format (json, csv), defaults to the output file extension or json",
+					},
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "output file, defaults to stdout",
+					},
 				},
+				Action: withCtrl(func(ctx *cli.Context, ctrl *commands.Controller) error {
+					out := ctx.String("output")
+					format := formatFromPath(ctx.String("format"), out)
+
+					var w io.Writer = os.Stdout
+					if out != "" {
+						f, err := os.Create(out)
+						if err != nil {
+							return err
+						}
+						defer f.Close()
+
+						w = f
+					}
+
+					return ctrl.Export(appctx, w, format)
+				}),
 			},
 			{
-				Name:  "search",
-				Usage: "search for repositories",
+				Name:      "import",
+				Usage:     "import repositories, artifacts, tags and pins from an export",
+				ArgsUsage: "<file|->",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "format",
+						Aliases: []string{"f"},
+						Usage:   "input format (json, csv), defaults to the input file extension or json",
+					},
+				},
+				Action: withCtrl(func(ctx *cli.Context, ctrl *commands.Controller) error {
+					in := ctx.Args().First()
+					if in == "" {
+						return fmt.Errorf("expected an input file or '-' for stdin")
+					}
+
+					var r io.Reader = os.Stdin
+					if in != "-" {
+						f, err := os.Open(in)
+						if err != nil {
+							return err
+						}
+						defer f.Close()
+
+						r = f
+					}
+
+					return ctrl.Import(appctx, r, formatFromPath(ctx.String("format"), in))
+				}),
+			},
+			{
+				Name:  "list",
+				Usage: "list repositories from the cache",
+				Flags: append(filterFlags(), &cli.StringFlag{
+					Name:    "format",
+					Aliases: []string{"f"},
+					Usage:   "output format (table, json, jsonl) or a template (e.g. '{{ .CloneDir }}')",
+					Value:   commands.FormatTable,
+				}),
+				Action: withCtrl(func(ctx *cli.Context, ctrl *commands.Controller) error {
+					return ctrl.List(appctx, os.Stdout, ctx.String("format"), repoFilter(ctx))
+				}),
+			},
+			{
+				Name:  "status",
+				Usage: "show the git status of cloned repositories",
+				Flags: append(filterFlags(),
+					&cli.BoolFlag{
+						Name:  "dirty",
+						Usage: "only show repositories with uncommitted or untracked changes",
+					},
+					&cli.BoolFlag{
+						Name:  "unpushed",
+						Usage: "only show repositories with unpushed commits, stashes or branches without an upstream",
+					},
+					&cli.StringFlag{
+						Name:    "format",
+						Aliases: []string{"f"},
+						Usage:   "output format (table, json, jsonl)",
+						Value:   commands.FormatTable,
+					},
+				),
+				Action: withCtrl(func(ctx *cli.Context, ctrl *commands.Controller) error {
+					return ctrl.Status(appctx, os.Stdout, ctx.String("format"), repoFilter(ctx), commands.StatusOptions{
+						Dirty:    ctx.Bool("dirty"),
+						Unpushed: ctx.Bool("unpushed"),
+					})
+				}),
+			},
+			{
+				Name:  "clone-all",
+				Usage: "clone every repository matching the filters that is not cloned yet",
+				Flags: append(append(filterFlags(), maintenanceFlags()...), &cli.BoolFlag{
+					Name:  "https",
+					Usage: "clone using the https url instead of ssh",
+				}),
+				Action: withCtrl(func(ctx *cli.Context, ctrl *commands.Controller) error {
+					clone := commands.CloneOptions{HTTPS: ctx.Bool("https")}
+					return ctrl.CloneAll(appctx, os.Stdout, repoFilter(ctx), clone, maintenanceOptions(ctx))
+				}),
+			},
+			{
+				Name:      "exec",
+				Usage:     "run a shell command in every matching cloned repository",
+				ArgsUsage: "-- <command>",
+				Flags:     filterFlags(),
+				Action: withCtrl(func(ctx *cli.Context, ctrl *commands.Controller) error {
+					return ctrl.Exec(appctx, os.Stdout, repoFilter(ctx), ctx.Args().Slice())
+				}),
+			},
+			{
+				Name:  "orphans",
+				Usage: "find clones that are not in the cache or not in their expected clone directory",
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:  "depth",
+						Usage: "maximum directory depth below each clone root to scan",
+						Value: 3,
+					},
+					&cli.StringFlag{
+						Name:    "format",
+						Aliases: []string{"f"},
+						Usage:   "output format (table, json)",
+						Value:   commands.FormatTable,
+					},
+				},
+				Action: withCtrl(func(ctx *cli.Context, ctrl *commands.Controller) error {
+					return ctrl.Orphans(appctx, os.Stdout, ctx.String("format"), ctx.Int("depth"))
+				}),
+			},
+			{
+				Name:  "relocate",
+				Usage: "move clones to the directory computed by the current clone directory matchers",
+				Flags: append(maintenanceFlags(),
+					&cli.IntFlag{
+						Name:  "depth",
+						Usage: "maximum directory depth below each clone root to scan",
+						Value: 3,
+					},
+					&cli.BoolFlag{
+						Name:  "undo",
+						Usage: "move the clones of the last relocate back",
+					},
+				),
+				Action: withCtrl(func(ctx *cli.Context, ctrl *commands.Controller) error {
+					if ctx.Bool("undo") {
+						return ctrl.RelocateUndo(appctx, maintenanceOptions(ctx))
+					}
+
+					return ctrl.Relocate(appctx, ctx.Int("depth"), maintenanceOptions(ctx))
+				}),
+			},
+			{
+				Name:  "daemon",
+				Usage: "sync the cache in the foreground every interval",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "interval",
+						Usage: "time between syncs, e.g. 30m or 1d (defaults to daemon.interval)",
+					},
+					&cli.BoolFlag{
+						Name:  "fetch",
+						Usage: "fetch the remotes of every cloned repository after each sync (defaults to daemon.fetch)",
+					},
+				},
+				Action: withCtrl(func(ctx *cli.Context, ctrl *commands.Controller) error {
+					opts := commands.DaemonOptions{
+						Interval: cfg.Daemon.Interval,
+						Fetch:    cfg.Daemon.Fetch,
+					}
+
+					if ctx.IsSet("interval") {
+						interval, err := duration.Parse(ctx.String("interval"))
+						if err != nil {
+							return err
+						}
+
+						opts.Interval = interval
+					}
+
+					if ctx.IsSet("fetch") {
+						opts.Fetch = ctx.Bool("fetch")
+					}
+
+					return ctrl.Daemon(appctx, opts)
+				}),
+			},
+			{
+				Name:         "pull",
+				Usage:        "fetch and fast-forward cloned repositories, skipping dirty working trees",
+				ArgsUsage:    "[owner/name]...",
+				BashComplete: completeRepos,
+				Flags:        filterFlags(),
+				Action: withCtrl(func(ctx *cli.Context, ctrl *commands.Controller) error {
+					return ctrl.Pull(appctx, ctx.Args().Slice(), repoFilter(ctx))
+				}),
+			},
+			{
+				Name:         "clone",
+				Usage:        "clone repositories into their configured clone directories",
+				ArgsUsage:    "<owner/name>...",
+				BashComplete: completeRepos,
+				Flags: append(filterFlags(), &cli.BoolFlag{
+					Name:  "https",
+					Usage: "clone using the https url instead of ssh",
+				}),
+				Action: withCtrl(func(ctx *cli.Context, ctrl *commands.Controller) error {
+					return ctrl.Clone(appctx, ctx.Args().Slice(), repoFilter(ctx), commands.CloneOptions{
+						HTTPS: ctx.Bool("https"),
+					})
+				}),
+			},
+			{
+				Name:         "shell-init",
+				Usage:        "print the shell integration to cd into the selected repository",
+				ArgsUsage:    "<bash|zsh|fish>",
+				BashComplete: completeShells,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "name",
+						Usage: "name of the shell function",
+						Value: shellinit.DefaultName,
+					},
+					&cli.StringFlag{
+						Name:  "key",
+						Usage: "bind the function to a key at the prompt (e.g. ctrl+f)",
+					},
+				},
 				Action: func(ctx *cli.Context) error {
-					db, err := sql.Open("sqlite", cfg.Database.DNS())
+					if ctx.NArg() != 1 {
+						return fmt.Errorf("expected one of %v", shellinit.Shells())
+					}
+
+					script, err := shellinit.Render(shellinit.Shell(ctx.Args().First()), shellinit.Options{
+						Name: ctx.String("name"),
+						Key:  ctx.String("key"),
+					})
 					if err != nil {
 						return err
 					}
-					defer db.Close()
 
-					ctrl, err := commands.NewController(cfg, db)
+					_, err = fmt.Fprint(os.Stdout, script)
+					return err
+				},
+			},
+			{
+				Name:  "config",
+				Usage: "manage the config file",
+				Subcommands: []*cli.Command{
+					{
+						Name:  "init",
+						Usage: "interactively create the config file at the --config path",
+						Action: func(ctx *cli.Context) error {
+							return commands.ConfigInit(cons, ctx.String("config"))
+						},
+					},
+				},
+			},
+			{
+				Name:  "doctor",
+				Usage: "check the config and environment for problems",
+				Action: func(ctx *cli.Context) error {
+					err := readConfig(ctx)
 					if err != nil {
-						return err
+						return commands.Doctor(appctx, cons, ctx.String("config"), cfg, err, nil)
 					}
 
-					msg, err := ctrl.Search(appctx)
+					return commands.Doctor(appctx, cons, ctx.String("config"), cfg, nil, setupEnvironment())
+				},
+			},
+			{
+				Name:         "completion",
+				Usage:        "print the shell completion script",
+				ArgsUsage:    "<bash|zsh|fish>",
+				BashComplete: completeShells,
+				Action: func(ctx *cli.Context) error {
+					if ctx.NArg() != 1 {
+						return fmt.Errorf("expected one of %v", shellinit.Shells())
+					}
+
+					script, err := shellinit.Completion(shellinit.Shell(ctx.Args().First()))
 					if err != nil {
 						return err
 					}
 
-					if msg != "" {
-						fmt.Println(msg)
+					_, err = fmt.Fprint(os.Stdout, script)
+					return err
+				},
+			},
+			{
+				Name:      "run",
+				Usage:     "run a configured key binding against a repository",
+				ArgsUsage: "<binding> <owner/name>",
+				BashComplete: completeWith(func(ctx *cli.Context, ctrl *commands.Controller) error {
+					switch ctx.NArg() {
+					case 0:
+						return ctrl.CompleteBindings(ctx.App.Writer)
+					case 1:
+						return ctrl.CompleteRepos(appctx, ctx.App.Writer)
+					default:
+						return nil
+					}
+				}),
+				Action: withCtrl(func(ctx *cli.Context, ctrl *commands.Controller) error {
+					if ctx.NArg() != 2 {
+						return fmt.Errorf("expected a key binding and a repository")
 					}
 
-					return nil
+					return ctrl.Run(appctx, os.Stdout, ctx.Args().Get(0), ctx.Args().Get(1))
+				}),
+			},
+			{
+				Name:  "tag",
+				Usage: "manage local tags and notes for repositories",
+				Subcommands: []*cli.Command{
+					{
+						Name:         "add",
+						Usage:        "add tags to a repository",
+						ArgsUsage:    "<owner/name> <tag>...",
+						BashComplete: completeRepoArg,
+						Action: withCtrl(func(ctx *cli.Context, ctrl *commands.Controller) error {
+							if ctx.NArg() < 2 {
+								return fmt.Errorf("expected a repository and at least one tag")
+							}
+
+							return ctrl.TagAdd(appctx, ctx.Args().First(), ctx.Args().Tail())
+						}),
+					},
+					{
+						Name:         "rm",
+						Usage:        "remove tags from a repository",
+						ArgsUsage:    "<owner/name> <tag>...",
+						BashComplete: completeRepoArg,
+						Action: withCtrl(func(ctx *cli.Context, ctrl *commands.Controller) error {
+							if ctx.NArg() < 2 {
+								return fmt.Errorf("expected a repository and at least one tag")
+							}
+
+							return ctrl.TagRemove(appctx, ctx.Args().First(), ctx.Args().Tail())
+						}),
+					},
+					{
+						Name:         "ls",
+						Usage:        "list tags and notes for a repository, or all tagged repositories",
+						ArgsUsage:    "[owner/name]",
+						BashComplete: completeRepoArg,
+						Action: withCtrl(func(ctx *cli.Context, ctrl *commands.Controller) error {
+							return ctrl.TagList(appctx, ctx.Args().First())
+						}),
+					},
+					{
+						Name:         "note",
+						Usage:        "set the note for a repository, an empty note removes it",
+						ArgsUsage:    "<owner/name> [note]",
+						BashComplete: completeRepoArg,
+						Action: withCtrl(func(ctx *cli.Context, ctrl *commands.Controller) error {
+							if ctx.NArg() < 1 {
+								return fmt.Errorf("expected a repository")
+							}
+
+							return ctrl.TagNote(appctx, ctx.Args().First(), strings.Join(ctx.Args().Tail(), " "))
+						}),
+					},
 				},
 			},
 			{
@@ -196,7 +893,9 @@ func main() {
 		case strings.HasPrefix(errstr, "flag provided but not defined"):
 		default:
 			log.Error().Err(err).Msg("error occurred")
-			cons.UnknownError("An unexpected error occurred", err)
+			// errors are written to stderr so they are not captured when the
+			// output of a command is used in a script, e.g. `cd $(repomgr search -q api --first)`
+			console.NewConsole(os.Stderr, true).UnknownError("An unexpected error occurred", err)
 		}
 
 		os.Exit(1)